@@ -0,0 +1,23 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	. "github.com/apmckinlay/gsuneido/runtime"
+)
+
+// Cmp returns -1, 0, or +1 using the same ordering as database indexes
+// i.e. booleans < numbers < strings < dates < objects
+var _ = builtin2("Cmp(x, y)", cmp)
+
+func cmp(x, y Value) Value {
+	c := x.Compare(y)
+	switch {
+	case c < 0:
+		return SuInt(-1)
+	case c > 0:
+		return One
+	}
+	return Zero
+}
@@ -0,0 +1,36 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+	"github.com/apmckinlay/gsuneido/util/dnum"
+)
+
+func TestCmp(t *testing.T) {
+	assert := assert.T(t).This
+	vals := []Value{False, True, SuDnum{Dnum: dnum.NegInf},
+		SuInt(-1), SuInt(0), SuInt(+1), SuDnum{Dnum: dnum.PosInf},
+		SuStr(""), SuStr("abc"), NewSuConcat().Add("foo"), SuStr("world"),
+		NewDate(2020, 1, 1, 0, 0, 0, 0), &SuObject{}}
+	for i, x := range vals {
+		for j, y := range vals {
+			expected := SuInt(0)
+			if i < j {
+				expected = SuInt(-1)
+			} else if i > j {
+				expected = SuInt(1)
+			}
+			assert(cmp(x, y)).Msg(x, " <> ", y).Is(expected)
+			// must match packed (database index) order
+			// except "" which packs as zero length so sorts first
+			if i < j && x != EmptyStr && y != EmptyStr {
+				assert(PackValue(x) < PackValue(y)).Is(true)
+			}
+		}
+	}
+}
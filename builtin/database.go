@@ -57,6 +57,12 @@ var databaseMethods = Methods{
 	"Nonce": method("()", func(t *Thread, this Value, args []Value) Value {
 		return SuStr(t.Dbms().Nonce())
 	}),
+	"Schema": method("(table)", func(t *Thread, this Value, args []Value) Value {
+		if ds, ok := t.Dbms().(ISchema); ok {
+			return ds.Schema(ToStr(args[0]))
+		}
+		panic("Database.Schema is not available when client-server")
+	}),
 	"SessionId": method("(id = '')", func(t *Thread, this Value, args []Value) Value {
 		return SuStr(t.Dbms().SessionId(ToStr(args[0])))
 	}),
@@ -0,0 +1,53 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+)
+
+// Digest returns the digest of a string, or of the contents of a file,
+// as raw bytes (like Sha256 and Md5).
+// Files are streamed through the hash rather than read into memory.
+var _ = builtin3("Digest(source, algorithm = 'sha256', fromFile = false)",
+	func(src, alg, fromFile Value) Value {
+		h := newDigest(ToStr(alg))
+		if ToBool(fromFile) {
+			digestFile(h, ToStr(src))
+		} else {
+			io.WriteString(h, ToStr(src))
+		}
+		return SuStr(string(h.Sum(nil)))
+	})
+
+func newDigest(alg string) hash.Hash {
+	switch strings.ToLower(alg) {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha256":
+		return sha256.New()
+	}
+	panic("Digest: unsupported algorithm: " + alg)
+}
+
+func digestFile(h hash.Hash, filename string) {
+	f, err := os.Open(filename)
+	if err != nil {
+		panic("Digest: can't " + err.Error())
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		panic("Digest: " + err.Error())
+	}
+}
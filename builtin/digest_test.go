@@ -0,0 +1,49 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestDigest(t *testing.T) {
+	assert := assert.T(t).This
+	th := NewThread()
+	digest := func(src, alg string, fromFile bool) string {
+		th.Push(SuStr(src))
+		th.Push(SuStr(alg))
+		th.Push(SuBool(fromFile))
+		result := Global.GetName(th, "Digest").Call(th, nil, &ArgSpec3)
+		return hex.EncodeToString([]byte(ToStr(result)))
+	}
+	const abcSha256 = "ba7816bf8f01cfea414140de5dae2223" +
+		"b00361a396177a9cb410ff61f20015ad"
+	assert(digest("abc", "sha256", false)).Is(abcSha256)
+	assert(digest("abc", "SHA1", false)).
+		Is("a9993e364706816aba3e25717850c26c9cd0d89d")
+	assert(digest("", "md5", false)).Is("d41d8cd98f00b204e9800998ecf8427e")
+	assert(func() { digest("abc", "foo", false) }).
+		Panics("unsupported algorithm")
+
+	// raw bytes, the same as the Sha256 builtin
+	th.Push(SuStr("abc"))
+	sha := Global.GetName(th, "Sha256").Call(th, nil, &ArgSpec1)
+	th.Push(SuStr("abc"))
+	assert(Global.GetName(th, "Digest").Call(th, nil, &ArgSpec1)).Is(sha)
+
+	f, err := ioutil.TempFile("", "digest")
+	assert(err).Is(nil)
+	defer os.Remove(f.Name())
+	f.WriteString("abc")
+	f.Close()
+	assert(digest(f.Name(), "sha256", true)).Is(abcSha256)
+	assert(func() { digest(f.Name()+"nonexistent", "sha256", true) }).
+		Panics("Digest: can't")
+}
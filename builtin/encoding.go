@@ -0,0 +1,46 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+)
+
+// Base64Encode and Base64Decode use standard (padded) base64
+// HexEncode uses lower case, HexDecode accepts either case
+
+var _ = builtin1("Base64Encode(string)", func(arg Value) Value {
+	return SuStr(base64.StdEncoding.EncodeToString([]byte(ToStr(arg))))
+})
+
+var _ = builtin1("Base64Decode(string)", func(arg Value) Value {
+	return SuStr(base64Decode(ToStr(arg)))
+})
+
+func base64Decode(s string) string {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		panic("Base64Decode: " + err.Error())
+	}
+	return string(b)
+}
+
+var _ = builtin1("HexEncode(string)", func(arg Value) Value {
+	return SuStr(hex.EncodeToString([]byte(ToStr(arg))))
+})
+
+var _ = builtin1("HexDecode(string)", func(arg Value) Value {
+	return SuStr(hexDecode(ToStr(arg)))
+})
+
+func hexDecode(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("HexDecode: " + err.Error())
+	}
+	return string(b)
+}
@@ -0,0 +1,37 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestEncoding(t *testing.T) {
+	assert := assert.T(t).This
+	th := NewThread()
+	call := func(name, s string) string {
+		th.Push(SuStr(s))
+		return ToStr(Global.GetName(th, name).Call(th, nil, &ArgSpec1))
+	}
+	test := func(s, b64, hx string) {
+		t.Helper()
+		assert(call("Base64Encode", s)).Is(b64)
+		assert(base64Decode(b64)).Is(s)
+		assert(call("HexEncode", s)).Is(hx)
+		assert(hexDecode(hx)).Is(s)
+	}
+	test("", "", "")
+	test("f", "Zg==", "66")
+	test("foobar", "Zm9vYmFy", "666f6f626172")
+	test("a\x00b\xff", "YQBi/w==", "610062ff")
+	assert(hexDecode("ABCDEF")).Is("\xab\xcd\xef")
+
+	assert(func() { base64Decode("Zg=") }).Panics("Base64Decode: illegal")
+	assert(func() { base64Decode("Z!==") }).Panics("Base64Decode: illegal")
+	assert(func() { hexDecode("abc") }).Panics("HexDecode: encoding/hex")
+	assert(func() { hexDecode("zz") }).Panics("HexDecode: encoding/hex")
+}
@@ -0,0 +1,71 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"io"
+	"os"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+)
+
+// FileReadAt returns up to length bytes from a file starting at offset.
+// It returns fewer bytes (possibly "") if it reaches the end of the file.
+var _ = builtin3("FileReadAt(filename, offset, length)",
+	func(name, offset, length Value) Value {
+		return SuStr(fileReadAt(ToStr(name), ToInt64(offset), ToInt(length)))
+	})
+
+func fileReadAt(name string, offset int64, length int) string {
+	if length < 0 {
+		panic("FileReadAt: length must not be negative")
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		panic("FileReadAt: can't " + err.Error())
+	}
+	defer f.Close()
+	checkOffset("FileReadAt", f, offset)
+	buf := make([]byte, length)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		panic("FileReadAt: " + err.Error())
+	}
+	return string(buf[:n])
+}
+
+// FileWriteAt writes data to a file starting at offset,
+// overwriting any existing data and extending the file if necessary.
+// The file is created if it does not exist.
+// offset must not be past the end of the file.
+var _ = builtin3("FileWriteAt(filename, offset, data)",
+	func(name, offset, data Value) Value {
+		fileWriteAt(ToStr(name), ToInt64(offset), ToStr(data))
+		return nil
+	})
+
+func fileWriteAt(name string, offset int64, data string) {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		panic("FileWriteAt: can't " + err.Error())
+	}
+	defer f.Close()
+	checkOffset("FileWriteAt", f, offset)
+	if _, err = f.WriteAt([]byte(data), offset); err != nil {
+		panic("FileWriteAt: " + err.Error())
+	}
+}
+
+func checkOffset(fn string, f *os.File, offset int64) {
+	if offset < 0 {
+		panic(fn + ": offset must not be negative")
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		panic(fn + ": " + err.Error())
+	}
+	if offset > fi.Size() {
+		panic(fn + ": offset past end of file")
+	}
+}
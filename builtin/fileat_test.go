@@ -0,0 +1,45 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestFileAt(t *testing.T) {
+	assert := assert.T(t)
+	f, err := ioutil.TempFile("", "fileat")
+	assert.This(err).Is(nil)
+	name := f.Name()
+	defer os.Remove(name)
+	f.WriteString("hello world")
+	f.Close()
+
+	assert.This(fileReadAt(name, 0, 5)).Is("hello")
+	assert.This(fileReadAt(name, 6, 5)).Is("world")
+	assert.This(fileReadAt(name, 6, 100)).Is("world") // short read
+	assert.This(fileReadAt(name, 11, 5)).Is("")
+	assert.This(fileReadAt(name, 3, 0)).Is("")
+	assert.This(func() { fileReadAt(name, 12, 1) }).Panics("past end of file")
+	assert.This(func() { fileReadAt(name, -1, 1) }).Panics("negative")
+	assert.This(func() { fileReadAt(name, 0, -1) }).Panics("negative")
+
+	fileWriteAt(name, 6, "WORLD")
+	assert.This(fileReadAt(name, 0, 100)).Is("hello WORLD")
+	fileWriteAt(name, 0, "J")
+	fileWriteAt(name, 11, "!\x00\xff") // append at end
+	assert.This(fileReadAt(name, 0, 100)).Is("Jello WORLD!\x00\xff")
+	assert.This(func() { fileWriteAt(name, 20, "x") }).Panics("past end of file")
+	assert.This(func() { fileWriteAt(name, -1, "x") }).Panics("negative")
+
+	os.Remove(name)
+	fileWriteAt(name, 0, "new") // creates
+	assert.This(fileReadAt(name, 0, 10)).Is("new")
+	os.Remove(name)
+	assert.This(func() { fileReadAt(name, 0, 1) }).Panics("FileReadAt: can't")
+}
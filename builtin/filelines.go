@@ -0,0 +1,72 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+)
+
+// FileLines returns a lazy sequence of the lines of a file.
+// Lines are read the same as File.Readline.
+// The file is not opened until the sequence is iterated
+// and it is closed when the iteration reaches the end
+// (or when the iterator is garbage collected)
+var _ = builtin1("FileLines(filename)",
+	func(arg Value) Value {
+		return NewSuSequence(&fileLinesIter{name: ToStr(arg)})
+	})
+
+type fileLinesIter struct {
+	name string
+	f    *os.File
+	r    *bufio.Reader
+	done bool
+}
+
+func (fl *fileLinesIter) Next() Value {
+	if fl.done {
+		return nil
+	}
+	if fl.f == nil {
+		fl.open()
+	}
+	line := Readline(fl.r, "FileLines: ")
+	if line == False {
+		fl.close()
+		fl.done = true
+		return nil
+	}
+	return line
+}
+
+func (fl *fileLinesIter) open() {
+	f, err := os.Open(fl.name)
+	if err != nil {
+		panic("FileLines: can't " + err.Error())
+	}
+	fl.f = f
+	fl.r = bufio.NewReader(f)
+	runtime.SetFinalizer(fl, (*fileLinesIter).close)
+}
+
+func (fl *fileLinesIter) close() {
+	if fl.f != nil {
+		fl.f.Close()
+		fl.f = nil
+		fl.r = nil
+		runtime.SetFinalizer(fl, nil)
+	}
+}
+
+func (fl *fileLinesIter) Dup() Iter {
+	return &fileLinesIter{name: fl.name}
+}
+
+func (fl *fileLinesIter) Infinite() bool {
+	return false
+}
@@ -0,0 +1,39 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestFileLines(t *testing.T) {
+	test := func(content string, expected ...string) {
+		t.Helper()
+		f, err := ioutil.TempFile("", "filelines")
+		assert.T(t).This(err).Is(nil)
+		defer os.Remove(f.Name())
+		f.WriteString(content)
+		f.Close()
+		fl := &fileLinesIter{name: f.Name()}
+		for i := 0; i < 2; i++ { // second time via Dup
+			var lines []string
+			for x := fl.Next(); x != nil; x = fl.Next() {
+				lines = append(lines, ToStr(x))
+			}
+			assert.T(t).This(lines).Is(expected)
+			assert.T(t).This(fl.f).Is(nil) // closed
+			fl = fl.Dup().(*fileLinesIter)
+		}
+	}
+	test("")
+	test("one", "one")
+	test("one\n", "one")
+	test("one\ntwo\r\nthree", "one", "two", "three")
+	test("one\n\nthree\n", "one", "", "three")
+}
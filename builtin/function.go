@@ -17,5 +17,22 @@ func init() {
 			Disasm(buf, fn)
 			return SuStr(buf.String())
 		}),
+		"Instructions": method0(func(this Value) Value {
+			return instructions(this.(*SuFunc))
+		}),
 	}
 }
+
+// instructions returns a list of the function's instructions,
+// each an object with ip, op, and arg members
+func instructions(fn *SuFunc) Value {
+	list := &SuObject{}
+	DisasmOps(fn, func(ip int, op, arg string) {
+		ob := &SuObject{}
+		ob.Set(SuStr("ip"), IntVal(ip))
+		ob.Set(SuStr("op"), SuStr(op))
+		ob.Set(SuStr("arg"), SuStr(arg))
+		list.Add(ob)
+	})
+	return list
+}
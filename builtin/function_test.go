@@ -0,0 +1,27 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	"github.com/apmckinlay/gsuneido/compile"
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestInstructions(t *testing.T) {
+	fn := compile.Constant("function (x) { return x + Foo }").(*SuFunc)
+	list := instructions(fn).(*SuObject)
+	var ops []string
+	for i := 0; i < list.ListSize(); i++ {
+		ob := list.ListGet(i).(*SuObject)
+		op := ToStr(ob.Get(nil, SuStr("op")))
+		arg := ToStr(ob.Get(nil, SuStr("arg")))
+		ops = append(ops, op+" "+arg)
+	}
+	assert.T(t).This(ops).Is([]string{"Load x", "Global Foo", "Add "})
+	ip := list.ListGet(1).(*SuObject).Get(nil, SuStr("ip"))
+	assert.T(t).This(ip).Is(IntVal(2))
+}
@@ -11,6 +11,11 @@ import (
 	"github.com/apmckinlay/gsuneido/util/dnum"
 )
 
+var _ = builtin2("Currency(number, scale = 2)",
+	func(n, scale Value) Value {
+		return NewSuCurrency(ToDnum(n), ToInt(scale))
+	})
+
 var minNarrow = dnum.FromInt(MinSuInt)
 var maxNarrow = dnum.FromInt(MaxSuInt)
 
@@ -60,6 +65,11 @@ func init() {
 			r := ToInt(arg)
 			return SuDnum{Dnum: x.Round(r, dnum.Down)}
 		}),
+		"RoundHalfEven": method1("(number)", func(this, arg Value) Value {
+			x := ToDnum(this)
+			r := ToInt(arg)
+			return SuDnum{Dnum: x.Round(r, dnum.HalfEven)}
+		}),
 
 		// float methods
 
@@ -102,19 +112,7 @@ func init() {
 			return fromFloat(math.Log10(f))
 		}),
 		"Pow": method1("(number)", func(this, arg Value) Value {
-			if p, ok := arg.ToInt(); ok && 0 <= p && p <= 10 {
-				if p == 0 {
-					return One
-				}
-				x := this
-				for ; p > 1; p-- {
-					x = OpMul(x, this)
-				}
-				return x
-			}
-			x := toFloat(this)
-			y := toFloat(arg)
-			return fromFloat(math.Pow(x, y))
+			return OpPow(this, arg)
 		}),
 		"Sqrt": method0(func(this Value) Value {
 			f := toFloat(this)
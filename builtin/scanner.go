@@ -4,6 +4,8 @@
 package builtin
 
 import (
+	"strings"
+
 	"github.com/apmckinlay/gsuneido/lexer"
 	"github.com/apmckinlay/gsuneido/lexer/tokens"
 	. "github.com/apmckinlay/gsuneido/runtime"
@@ -15,6 +17,10 @@ type SuScanner struct {
 	lxr  lexer.Lexer
 	item lexer.Item
 	name string
+	// skipWhite makes Next skip Whitespace and Newline tokens
+	skipWhite bool
+	// skipComments makes Next skip Comment tokens
+	skipComments bool
 }
 
 var _ = builtin1("Scanner(string)",
@@ -22,6 +28,59 @@ var _ = builtin1("Scanner(string)",
 		return &SuScanner{lxr: *lexer.NewLexer(ToStr(arg)), name: "Scanner"}
 	})
 
+// ScannerTokens returns an object mapping token names to the numeric codes
+// returned by scanner.Token() e.g. ScannerTokens().Identifier
+// This allows switching on the code rather than comparing Type() strings.
+// The codes are stable for a given executable but may change between versions
+// so they should not be stored.
+var _ = builtin0("ScannerTokens()", func() Value {
+	return scannerTokens
+})
+
+var scannerTokens = func() *SuObject {
+	ob := &SuObject{}
+	for tok := tokens.Nil; int(tok) <= int(tokens.Where); tok++ {
+		name := tok.String()
+		if strings.HasSuffix(name, "Start") || strings.HasSuffix(name, "End") {
+			continue // markers, not actual tokens
+		}
+		ob.Set(SuStr(name), IntVal(int(tok)))
+	}
+	ob.SetReadOnly()
+	return ob
+}()
+
+// Tokenize scans an entire string and returns a list of objects
+// with text, type (as from scanner.Type), pos, and len members.
+// mode may be "query" to use the query lexer.
+var _ = builtin2("Tokenize(string, mode = '')",
+	func(arg, mode Value) Value {
+		return tokenize(ToStr(arg), ToStr(mode))
+	})
+
+func tokenize(src, mode string) *SuObject {
+	sc := &SuScanner{lxr: *lexer.NewLexer(src)}
+	switch mode {
+	case "":
+	case "query":
+		sc.lxr = *lexer.NewQueryLexer(src)
+	default:
+		panic("Tokenize: invalid mode: " + mode)
+	}
+	list := &SuObject{}
+	for sc.advance(); sc.item.Token != tokens.Eof; sc.advance() {
+		from := int(sc.item.Pos)
+		to := sc.lxr.Position()
+		ob := &SuObject{}
+		ob.Set(SuStr("text"), SuStr(src[from:to]))
+		ob.Set(SuStr("type"), SuStr(sc.type2()))
+		ob.Set(SuStr("pos"), IntVal(from))
+		ob.Set(SuStr("len"), IntVal(to-from))
+		list.Add(ob)
+	}
+	return list
+}
+
 var _ Value = (*SuScanner)(nil)
 
 func (sc *SuScanner) Get(*Thread, Value) Value {
@@ -88,7 +147,7 @@ var scannerMethods = Methods{
 	}),
 	"Next2": method0(func(this Value) Value {
 		sc := this.(*SuScanner)
-		sc.item = sc.lxr.Next()
+		sc.advance()
 		if sc.item.Token == tokens.Eof {
 			return sc
 		}
@@ -97,9 +156,33 @@ var scannerMethods = Methods{
 	"Position": method0(func(this Value) Value {
 		return IntVal(this.(*SuScanner).lxr.Position())
 	}),
+	"Reset": method0(func(this Value) Value {
+		sc := this.(*SuScanner)
+		sc.reset()
+		return sc
+	}),
+	"SetPosition": method1("(pos)", func(this, arg Value) Value {
+		sc := this.(*SuScanner)
+		sc.lxr.SetPosition(ToInt(arg))
+		sc.item = lexer.Item{}
+		return sc
+	}),
+	"SkipComments": method1("(skip = true)", func(this, arg Value) Value {
+		sc := this.(*SuScanner)
+		sc.skipComments = ToBool(arg)
+		return sc
+	}),
+	"SkipWhitespace": method1("(skip = true)", func(this, arg Value) Value {
+		sc := this.(*SuScanner)
+		sc.skipWhite = ToBool(arg)
+		return sc
+	}),
 	"Text": method0(func(this Value) Value {
 		return this.(*SuScanner).text()
 	}),
+	"Token": method0(func(this Value) Value {
+		return IntVal(int(this.(*SuScanner).item.Token))
+	}),
 	"Type": method0(func(this Value) Value {
 		return SuStr(this.(*SuScanner).type2())
 	}),
@@ -109,13 +192,40 @@ var scannerMethods = Methods{
 }
 
 func (sc *SuScanner) next() Value {
-	sc.item = sc.lxr.Next()
+	sc.advance()
 	if sc.item.Token == tokens.Eof {
 		return sc
 	}
 	return sc.text()
 }
 
+// advance moves to the next item, skipping whitespace and/or comments
+// if requested
+func (sc *SuScanner) advance() {
+	for {
+		sc.item = sc.lxr.Next()
+		if !sc.skip(sc.item.Token) {
+			return
+		}
+	}
+}
+
+func (sc *SuScanner) skip(tok tokens.Token) bool {
+	switch tok {
+	case tokens.Whitespace, tokens.Newline:
+		return sc.skipWhite
+	case tokens.Comment:
+		return sc.skipComments
+	}
+	return false
+}
+
+// reset restarts scanning from the beginning of the source
+func (sc *SuScanner) reset() {
+	sc.lxr = *sc.lxr.Dup()
+	sc.item = lexer.Item{}
+}
+
 func (sc *SuScanner) text() Value {
 	src := sc.lxr.Source()
 	from := sc.item.Pos
@@ -168,7 +278,8 @@ func (sc *SuScanner) Next() Value {
 }
 
 func (sc *SuScanner) Dup() Iter {
-	return &SuScanner{lxr: *sc.lxr.Dup()}
+	return &SuScanner{lxr: *sc.lxr.Dup(), name: sc.name,
+		skipWhite: sc.skipWhite, skipComments: sc.skipComments}
 }
 
 func (sc *SuScanner) Infinite() bool {
@@ -0,0 +1,106 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	"github.com/apmckinlay/gsuneido/lexer"
+	"github.com/apmckinlay/gsuneido/lexer/tokens"
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func scanAll(sc *SuScanner) []string {
+	var list []string
+	for tok := sc.Next(); tok != nil; tok = sc.Next() {
+		list = append(list, ToStr(tok))
+	}
+	return list
+}
+
+func TestScannerReset(t *testing.T) {
+	assert := assert.T(t)
+	sc := &SuScanner{lxr: *lexer.NewLexer("x = 12 // comment"),
+		name: "Scanner"}
+	first := scanAll(sc)
+	assert.This(len(first)).Is(7)
+	assert.This(sc.Next()).Is(nil)
+	sc.reset()
+	assert.This(scanAll(sc)).Is(first)
+}
+
+func TestScannerSkip(t *testing.T) {
+	test := func(skipWhite, skipComments bool, expected ...string) {
+		t.Helper()
+		sc := &SuScanner{lxr: *lexer.NewLexer("a /* x */ b\n// y\nc"),
+			skipWhite: skipWhite, skipComments: skipComments}
+		assert.T(t).This(scanAll(sc)).Is(expected)
+	}
+	test(false, false, "a", " ", "/* x */", " ", "b", "\n", "// y", "\n", "c")
+	test(true, false, "a", "/* x */", "b", "// y", "c")
+	test(false, true, "a", " ", " ", "b", "\n", "\n", "c")
+	test(true, true, "a", "b", "c")
+}
+
+func TestScannerToken(t *testing.T) {
+	assert := assert.T(t)
+	code := func(name string) Value {
+		return scannerTokens.Get(nil, SuStr(name))
+	}
+	assert.This(code("Identifier")).Is(IntVal(int(tokens.Identifier)))
+	assert.This(code("Eof")).Is(IntVal(int(tokens.Eof)))
+	assert.This(code("OpsStart")).Is(nil)
+	th := NewThread()
+	sc := &SuScanner{lxr: *lexer.NewLexer("x = 12 // c"), skipWhite: true}
+	var names []string
+	for tok := sc.Next(); tok != nil; tok = sc.Next() {
+		tc := scannerMethods["Token"].Call(th, sc, &ArgSpec0)
+		for _, name := range []string{"Identifier", "Eq", "Number", "Comment"} {
+			if code(name).Equal(tc) {
+				names = append(names, name)
+			}
+		}
+	}
+	assert.This(names).Is([]string{"Identifier", "Eq", "Number", "Comment"})
+}
+
+func TestTokenize(t *testing.T) {
+	test := func(src, mode string, expected ...string) {
+		t.Helper()
+		list := tokenize(src, mode)
+		assert.T(t).This(list.ListSize()).Is(len(expected))
+		for i, e := range expected {
+			ob := list.ListGet(i).(*SuObject)
+			s := ToStr(ob.Get(nil, SuStr("text"))) + " " +
+				ToStr(ob.Get(nil, SuStr("type"))) + " " +
+				ob.Get(nil, SuStr("pos")).String() + " " +
+				ob.Get(nil, SuStr("len")).String()
+			assert.T(t).This(s).Is(e)
+		}
+	}
+	test("", "")
+	test("x = 12 // c", "",
+		"x IDENTIFIER 0 1", "  WHITESPACE 1 1", "=  2 1", "  WHITESPACE 3 1",
+		"12 NUMBER 4 2", "  WHITESPACE 6 1", "// c COMMENT 7 4")
+	test(`F("s")`, "", "F IDENTIFIER 0 1", "(  1 1", `"s" STRING 2 3`,
+		")  5 1")
+	test("tbl where a", "query", "tbl IDENTIFIER 0 3", "  WHITESPACE 3 1",
+		"where IDENTIFIER 4 5", "  WHITESPACE 9 1", "a IDENTIFIER 10 1")
+	assert.T(t).This(func() { tokenize("x", "foo") }).Panics("invalid mode")
+}
+
+func TestScannerSetPosition(t *testing.T) {
+	src := "function (x)\n\t{\n\ts = 'a\nb' // c\n\treturn x + 1\n\t}"
+	sc := &SuScanner{lxr: *lexer.NewLexer(src)}
+	full := scanAll(sc)
+	pos := 0
+	th := NewThread()
+	for i, tok := range full {
+		th.Push(IntVal(pos))
+		scannerMethods["SetPosition"].Call(th, sc, &ArgSpec1)
+		assert.T(t).This(scanAll(sc)).Is(full[i:])
+		pos += len(tok)
+	}
+}
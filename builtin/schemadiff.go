@@ -0,0 +1,67 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"github.com/apmckinlay/gsuneido/compile"
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/setord"
+)
+
+// SchemaDiff compares two schemas e.g. "(a,b) key(a)"
+// and returns an object with the added and removed columns and indexes.
+// A changed index is returned as removed and added.
+var _ = builtin2("SchemaDiff(from, to)", func(from, to Value) Value {
+	return schemaDiff(ToStr(from), ToStr(to))
+})
+
+func schemaDiff(from, to string) *SuObject {
+	fs := parseSchema(from)
+	ts := parseSchema(to)
+	fcols, tcols := schemaColumns(fs), schemaColumns(ts)
+	fixs, tixs := schemaIndexes(fs), schemaIndexes(ts)
+	ob := &SuObject{}
+	ob.Set(SuStr("addedColumns"), diffList(setord.Difference(tcols, fcols)))
+	ob.Set(SuStr("removedColumns"), diffList(setord.Difference(fcols, tcols)))
+	ob.Set(SuStr("addedIndexes"), diffList(setord.Difference(tixs, fixs)))
+	ob.Set(SuStr("removedIndexes"), diffList(setord.Difference(fixs, tixs)))
+	return ob
+}
+
+func parseSchema(s string) *compile.Schema {
+	return &compile.ParseRequest("ensure schemadiff " + s).Schema
+}
+
+// schemaColumns returns the (non-deleted) columns, including derived,
+// as single element lists for setord
+func schemaColumns(sc *compile.Schema) [][]string {
+	cols := make([][]string, 0, len(sc.Columns)+len(sc.Derived))
+	for _, col := range sc.Columns {
+		if col != "-" {
+			cols = append(cols, []string{col})
+		}
+	}
+	for _, col := range sc.Derived {
+		cols = append(cols, []string{col})
+	}
+	return cols
+}
+
+// schemaIndexes returns the index definitions
+// as single element lists for setord
+func schemaIndexes(sc *compile.Schema) [][]string {
+	ixs := make([][]string, len(sc.Indexes))
+	for i := range sc.Indexes {
+		ixs[i] = []string{sc.Indexes[i].String()}
+	}
+	return ixs
+}
+
+func diffList(list [][]string) Value {
+	ob := &SuObject{}
+	for _, x := range list {
+		ob.Add(SuStr(x[0]))
+	}
+	return ob
+}
@@ -0,0 +1,43 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	test := func(from, to string, expected string) {
+		t.Helper()
+		ob := schemaDiff(from, to)
+		actual := ""
+		for _, mem := range []string{"addedColumns", "removedColumns",
+			"addedIndexes", "removedIndexes"} {
+			actual += mem + ": " + ob.Get(nil, SuStr(mem)).String() + " "
+		}
+		assert.T(t).This(actual).Like(expected)
+	}
+	test("(a,b) key(a)", "(a,b) key(a)",
+		"addedColumns: #() removedColumns: #() "+
+			"addedIndexes: #() removedIndexes: #()")
+	// added columns
+	test("(a,b) key(a)", "(a,b,c,D) key(a)",
+		`addedColumns: #("c", "D") removedColumns: #() `+
+			"addedIndexes: #() removedIndexes: #()")
+	// removed column, dropped index
+	test("(a,b,c) key(a) index(b)", "(a,-,c) key(a)",
+		`addedColumns: #() removedColumns: #("b") `+
+			`addedIndexes: #() removedIndexes: #("index(b)")`)
+	// changed key
+	test("(a,b) key(a)", "(a,b) key(a,b)",
+		`addedColumns: #() removedColumns: #() `+
+			`addedIndexes: #("key(a,b)") removedIndexes: #("key(a)")`)
+	// key changed to index
+	test("(a,b) key(a) key(b)", "(a,b) key(a) index(b)",
+		`addedColumns: #() removedColumns: #() `+
+			`addedIndexes: #("index(b)") removedIndexes: #("key(b)")`)
+}
@@ -52,3 +52,113 @@ func (seq *seqIter) Dup() Iter {
 func (seq *seqIter) Infinite() bool {
 	return seq.to == math.MaxInt32 // has to match runtime.MaxInt
 }
+
+// CharSeq yields the single character strings from first to last inclusive
+// e.g. CharSeq("a", "e") => "a", "b", "c", "d", "e"
+// If last is before first the sequence is empty.
+var _ = builtin2("CharSeq(first, last)",
+	func(first, last Value) Value {
+		f := seqChar(first)
+		return NewSuSequence(&charSeqIter{f, seqChar(last), int(f)})
+	})
+
+func seqChar(x Value) byte {
+	s := ToStr(x)
+	if len(s) != 1 {
+		panic("CharSeq: arguments must be single characters")
+	}
+	return s[0]
+}
+
+type charSeqIter struct {
+	first byte
+	last  byte
+	i     int
+}
+
+func (seq *charSeqIter) Next() Value {
+	if seq.i > int(seq.last) {
+		return nil
+	}
+	c := seq.i
+	seq.i++
+	return SuStr(string([]byte{byte(c)}))
+}
+
+func (seq *charSeqIter) Dup() Iter {
+	return &charSeqIter{seq.first, seq.last, int(seq.first)}
+}
+
+func (seq *charSeqIter) Infinite() bool {
+	return false
+}
+
+// DateSeq yields the dates from first to last inclusive,
+// advancing by the given number of days and/or months (default one day).
+// Each date is computed from first (not from the previous date)
+// so stepping by months from the 31st does not drift.
+// Stepping by months clamps to the end of shorter months
+// e.g. from #20200131 => #20200229, #20200331, #20200430
+// If last is before first the sequence is empty.
+var _ = builtin4("DateSeq(first, last, days = 0, months = 0)",
+	func(first, last, days, months Value) Value {
+		d := ToInt(days)
+		m := ToInt(months)
+		if d < 0 || m < 0 {
+			panic("DateSeq: step must not be negative")
+		}
+		if d == 0 && m == 0 {
+			d = 1
+		}
+		return NewSuSequence(&dateSeqIter{first: seqDate(first),
+			last: seqDate(last), days: d, months: m})
+	})
+
+func seqDate(x Value) SuDate {
+	if d, ok := x.(SuDate); ok {
+		return d
+	}
+	panic("DateSeq: arguments must be dates")
+}
+
+type dateSeqIter struct {
+	first  SuDate
+	last   SuDate
+	days   int
+	months int
+	i      int
+}
+
+func (seq *dateSeqIter) Next() Value {
+	d := addMonths(seq.first, seq.i*seq.months).
+		Plus(0, 0, seq.i*seq.days, 0, 0, 0, 0)
+	if d.Compare(seq.last) > 0 {
+		return nil
+	}
+	seq.i++
+	return d
+}
+
+// addMonths adds n months to d,
+// clamping the day to the last day of the resulting month
+func addMonths(d SuDate, n int) SuDate {
+	m := d.Month() - 1 + n
+	yr := d.Year() + m/12
+	mon := m%12 + 1
+	day := d.Day()
+	// day 0 of the following month is the last day of this month
+	if last := NormalizeDate(yr, mon+1, 0, 0, 0, 0, 0).Day(); day > last {
+		day = last
+	}
+	return NewDate(yr, mon, day,
+		d.Hour(), d.Minute(), d.Second(), d.Millisecond())
+}
+
+func (seq *dateSeqIter) Dup() Iter {
+	return &dateSeqIter{first: seq.first, last: seq.last,
+		days: seq.days, months: seq.months}
+}
+
+func (seq *dateSeqIter) Infinite() bool {
+	return false
+}
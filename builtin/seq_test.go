@@ -0,0 +1,63 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestCharSeq(t *testing.T) {
+	th := NewThread()
+	test := func(first, last, expected string) {
+		t.Helper()
+		th.Push(SuStr(first))
+		th.Push(SuStr(last))
+		seq := Global.GetName(th, "CharSeq").Call(th, nil, &ArgSpec2)
+		assert.T(t).This(seqString(seq)).Is(expected)
+	}
+	test("a", "e", "abcde")
+	test("x", "x", "x")
+	test("e", "a", "")
+	test("0", "9", "0123456789")
+	assert.T(t).This(func() { test("ab", "z", "") }).
+		Panics("single characters")
+}
+
+func TestDateSeq(t *testing.T) {
+	th := NewThread()
+	test := func(first, last string, days, months int, expected string) {
+		t.Helper()
+		th.Push(DateFromLiteral(first))
+		th.Push(DateFromLiteral(last))
+		th.Push(IntVal(days))
+		th.Push(IntVal(months))
+		seq := Global.GetName(th, "DateSeq").Call(th, nil, &ArgSpec4)
+		assert.T(t).This(seqString(seq)).Is(expected)
+	}
+	test("#20200227", "#20200302", 0, 0,
+		"#20200227#20200228#20200229#20200301#20200302")
+	test("#20200101", "#20200120", 7, 0, "#20200101#20200108#20200115")
+	test("#20200131", "#20200601", 0, 1,
+		"#20200131#20200229#20200331#20200430#20200531")
+	test("#20191130", "#20200301", 0, 1,
+		"#20191130#20191230#20200130#20200229")
+	test("#20200229", "#20210301", 0, 12, "#20200229#20210228")
+	test("#20200131", "#20200401", 1, 1, "#20200131#20200301")
+	test("#20200115", "#20200115", 1, 0, "#20200115")
+	test("#20200115", "#20200101", 1, 0, "")
+	assert.T(t).This(func() { test("#20200101", "#20200102", -1, 0, "") }).
+		Panics("must not be negative")
+}
+
+func seqString(seq Value) string {
+	s := ""
+	iter := seq.(*SuSequence).Iter()
+	for x := iter.Next(); x != nil; x = iter.Next() {
+		s += ToStrOrString(x)
+	}
+	return s
+}
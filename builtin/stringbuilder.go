@@ -0,0 +1,88 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/runtime/types"
+)
+
+// SuStringBuilder incrementally builds a string.
+// It uses an SuConcat so appends are amortized O(1)
+// and ToString does not copy.
+type SuStringBuilder struct {
+	CantConvert
+	sc SuConcat
+}
+
+var _ = builtin0("StringBuilder()",
+	func() Value {
+		return &SuStringBuilder{sc: NewSuConcat()}
+	})
+
+var _ Value = (*SuStringBuilder)(nil)
+
+func (*SuStringBuilder) Get(*Thread, Value) Value {
+	panic("StringBuilder does not support get")
+}
+
+func (*SuStringBuilder) Put(*Thread, Value, Value) {
+	panic("StringBuilder does not support put")
+}
+
+func (*SuStringBuilder) RangeTo(int, int) Value {
+	panic("StringBuilder does not support range")
+}
+
+func (*SuStringBuilder) RangeLen(int, int) Value {
+	panic("StringBuilder does not support range")
+}
+
+func (*SuStringBuilder) Hash() uint32 {
+	panic("StringBuilder hash not implemented")
+}
+
+func (*SuStringBuilder) Hash2() uint32 {
+	panic("StringBuilder hash not implemented")
+}
+
+func (*SuStringBuilder) Compare(Value) int {
+	panic("StringBuilder compare not implemented")
+}
+
+func (*SuStringBuilder) Call(*Thread, Value, *ArgSpec) Value {
+	panic("can't call StringBuilder")
+}
+
+func (*SuStringBuilder) String() string {
+	return "aStringBuilder"
+}
+
+func (*SuStringBuilder) Type() types.Type {
+	return types.BuiltinInstance
+}
+
+func (sb *SuStringBuilder) Equal(other interface{}) bool {
+	sb2, ok := other.(*SuStringBuilder)
+	return ok && sb == sb2
+}
+
+func (*SuStringBuilder) Lookup(_ *Thread, method string) Callable {
+	return stringBuilderMethods[method]
+}
+
+var stringBuilderMethods = Methods{
+	"Append": method1("(string)", func(this, arg Value) Value {
+		sb := this.(*SuStringBuilder)
+		sb.sc = sb.sc.Add(AsStr(arg))
+		return this
+	}),
+	"Size": method0(func(this Value) Value {
+		return IntVal(this.(*SuStringBuilder).sc.Len())
+	}),
+	"ToString": method0(func(this Value) Value {
+		// later appends do not affect the returned value
+		return this.(*SuStringBuilder).sc
+	}),
+}
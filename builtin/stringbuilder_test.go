@@ -0,0 +1,36 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestStringBuilder(t *testing.T) {
+	assert := assert.T(t).This
+	sb := &SuStringBuilder{sc: NewSuConcat()}
+	th := NewThread()
+	call := func(method string, args ...Value) Value {
+		for _, arg := range args {
+			th.Push(arg)
+		}
+		as := &ArgSpec0
+		if len(args) == 1 {
+			as = &ArgSpec1
+		}
+		return stringBuilderMethods[method].Call(th, sb, as)
+	}
+	assert(call("ToString")).Is(SuStr(""))
+	call("Append", SuStr("hello"))
+	call("Append", SuInt(123))
+	s := call("ToString")
+	assert(s).Is(SuStr("hello123"))
+	call("Append", SuStr(" world"))
+	assert(s).Is(SuStr("hello123")) // unaffected by later appends
+	assert(call("ToString")).Is(SuStr("hello123 world"))
+	assert(call("Size")).Is(SuInt(14))
+}
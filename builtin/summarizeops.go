@@ -0,0 +1,23 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"github.com/apmckinlay/gsuneido/compile"
+	. "github.com/apmckinlay/gsuneido/runtime"
+)
+
+// SummarizeOps returns a list of the aggregate operations
+// supported by summarize e.g. count, total
+var _ = builtin0("SummarizeOps()", func() Value {
+	return summarizeOps()
+})
+
+func summarizeOps() *SuObject {
+	ob := &SuObject{}
+	for _, op := range compile.SummarizeOps {
+		ob.Add(SuStr(op))
+	}
+	return ob
+}
@@ -0,0 +1,18 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestSummarizeOps(t *testing.T) {
+	ob := summarizeOps()
+	for _, op := range []string{"count", "total", "average", "min", "max", "list"} {
+		assert.T(t).Msg(op).That(ob.Find(SuStr(op)) != nil)
+	}
+}
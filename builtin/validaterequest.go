@@ -0,0 +1,41 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"github.com/apmckinlay/gsuneido/compile"
+	. "github.com/apmckinlay/gsuneido/runtime"
+)
+
+// ValidateRequest parses and checks a database request (e.g. create, alter)
+// without executing it. It returns true if the request is valid,
+// otherwise an object with the error e.g.
+// #(type: "syntax", error: "...", pos: 12)
+// or #(type: "schema", error: "...", columns: #(...))
+var _ = builtin1("ValidateRequest(request)", func(arg Value) Value {
+	return validateRequest(ToStr(arg))
+})
+
+func validateRequest(request string) Value {
+	_, err := compile.CheckRequest(request)
+	if err == nil {
+		return True
+	}
+	ob := &SuObject{}
+	switch e := err.(type) {
+	case *compile.ParseError:
+		ob.Set(SuStr("type"), SuStr("syntax"))
+		ob.Set(SuStr("error"), SuStr(e.Msg))
+		ob.Set(SuStr("pos"), IntVal(e.Pos))
+	case *compile.SchemaError:
+		ob.Set(SuStr("type"), SuStr("schema"))
+		ob.Set(SuStr("error"), SuStr(e.Msg))
+		cols := &SuObject{}
+		for _, col := range e.Columns {
+			cols.Add(SuStr(col))
+		}
+		ob.Set(SuStr("columns"), cols)
+	}
+	return ob
+}
@@ -0,0 +1,40 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package builtin
+
+import (
+	"testing"
+
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestValidateRequest(t *testing.T) {
+	assert := assert.T(t).This
+	assert(validateRequest("create mytable (one,two) key(one)")).Is(True)
+	assert(validateRequest("alter mytable rename one to two")).Is(True)
+
+	get := func(v Value, mem string) string {
+		return v.(*SuObject).Get(nil, SuStr(mem)).String()
+	}
+	v := validateRequest("create mytable (one,two) key(one")
+	assert(get(v, "type")).Is(`"syntax"`)
+	assert(get(v, "error")).Is(`"expecting identifier"`)
+	assert(get(v, "pos")).Is("32")
+
+	test := func(rq, err, cols string) {
+		t.Helper()
+		v := validateRequest(rq)
+		assert(get(v, "type")).Is(`"schema"`)
+		assert(get(v, "error")).Is(err)
+		assert(get(v, "columns")).Is(cols)
+	}
+	test("create mytable (one,two) key(three)",
+		`"invalid index column: three"`, `#("three")`)
+	test("create mytable (one,two) index(one)", `"key required"`, "#()")
+	test("create mytable (one,two) key(one) index(one,two) index(one,two)",
+		`"duplicate index: (one,two)"`, `#("one", "two")`)
+	test("create mytable (one,two,one) key(one)",
+		`"duplicate column: one"`, `#("one")`)
+}
@@ -525,15 +525,22 @@ func (x *Forever) Children(fn func(Node) Node) {
 	applyStmt(fn, &x.Body)
 }
 
+// ForIn is for var in expr
+// or for index, var in expr (Index.Name is "" if there is no index)
 type ForIn struct {
 	stmtNodeT
-	Var  Ident
-	E    Expr
-	Body Statement
+	Index Ident
+	Var   Ident
+	E     Expr
+	Body  Statement
 }
 
 func (x *ForIn) String() string {
-	return "ForIn(" + x.Var.Name + " " + x.E.String() + "\n" + x.Body.String() + ")"
+	s := "ForIn("
+	if x.Index.Name != "" {
+		s += x.Index.Name + ","
+	}
+	return s + x.Var.Name + " " + x.E.String() + "\n" + x.Body.String() + ")"
 }
 
 func (x *ForIn) Children(fn func(Node) Node) {
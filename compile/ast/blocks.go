@@ -141,6 +141,9 @@ func (b *bloks) statement(stmt Statement, vars set) {
 			b.statement(d, vars)
 		}
 	case *ForIn:
+		if stmt.Index.Name != "" {
+			vars[stmt.Index.Name] = yes
+		}
 		vars[stmt.Var.Name] = yes
 		b.expr(stmt.E, vars)
 		b.statement(stmt.Body, vars)
@@ -195,6 +195,9 @@ func (ck *Check) statement(
 		}
 		init = init.union(initInAll)
 	case *ast.ForIn:
+		if stmt.Index.Name != "" {
+			init = ck.initVar(init, stmt.Index.Name, int(stmt.Index.Pos))
+		}
 		init = ck.initVar(init, stmt.Var.Name, int(stmt.Var.Pos))
 		init, _ = ck.expr(stmt.E, init)
 		ck.statement(stmt.Body, init, false)
@@ -445,6 +445,10 @@ func (cg *cgen) forStmt(node *ast.For) {
 func (cg *cgen) forInStmt(node *ast.ForIn) {
 	cg.expr(node.E)
 	cg.emit(op.Iter)
+	if node.Index.Name != "" {
+		cg.forInIxStmt(node)
+		return
+	}
 	labels := cg.newLabels()
 	cg.emitForIn(node.Var.Name, labels)
 	cg.statement(node.Body, labels, false)
@@ -453,6 +457,22 @@ func (cg *cgen) forInStmt(node *ast.ForIn) {
 	cg.emit(op.Pop)
 }
 
+// forInIxStmt keeps the index on the stack above the iterator
+func (cg *cgen) forInIxStmt(node *ast.ForIn) {
+	cg.emit(op.Zero)
+	labels := cg.newLabels()
+	ix := cg.name(node.Index.Name)
+	i := cg.name(node.Var.Name)
+	adr := len(cg.code)
+	cg.emit(op.ForInIx, byte(labels.brk>>8), byte(labels.brk), byte(ix), byte(i))
+	labels.brk = adr
+	cg.statement(node.Body, labels, false)
+	cg.emitBwdJump(op.Jump, labels.cont)
+	cg.placeLabel(labels.brk)
+	cg.emit(op.Pop)
+	cg.emit(op.Pop)
+}
+
 func (cg *cgen) emitForIn(name string, labels *Labels) {
 	i := cg.name(name)
 	adr := len(cg.code)
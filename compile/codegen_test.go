@@ -505,6 +505,18 @@ func TestControl(t *testing.T) {
         13: Jump 3
         16: Jump 3
         19: Pop`)
+
+	test(`for (i, x in y) { a; break }`, `
+		0: Load y
+        2: Iter
+        3: Zero
+        4: ForInIx i x 18
+        9: Load a
+        11: Pop
+        12: Jump 18
+        15: Jump 4
+        18: Pop
+        19: Pop`)
 }
 
 func TestBlock(t *testing.T) {
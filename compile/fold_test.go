@@ -100,6 +100,8 @@ func TestPropFold(t *testing.T) {
 
 	// binary
 	test("8 % 3", "2")
+	test("-8 % 3", "-2")
+	test("8.5 % 3", "2")
 	test("1 << 4", "16")
 	test("'foobar' =~ 'oo'", "true")
 	test("'foobar' !~ 'obo'", "true")
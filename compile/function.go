@@ -296,22 +296,38 @@ func (p *parser) isForIn() bool {
 	if !p.lxr.AheadSkip(i).Token.IsIdent() {
 		return false
 	}
+	if p.lxr.AheadSkip(i+1).Token == tok.Comma { // for index, var in
+		i += 2
+		if !p.lxr.AheadSkip(i).Token.IsIdent() {
+			return false
+		}
+	}
 	return p.lxr.AheadSkip(i+1).Token == tok.In
 }
 
 func (p *parser) forIn() *ast.ForIn {
 	parens := p.matchIf(tok.LParen)
-	id := p.Text
-	p.final[id] = disqualified
-	pos := p.Pos
-	p.matchIdent()
+	var index ast.Ident
+	if p.lxr.AheadSkip(0).Token == tok.Comma {
+		index = p.forInVar()
+		p.match(tok.Comma)
+	}
+	v := p.forInVar()
 	p.match(tok.In)
 	expr := p.exprExpecting(!parens)
 	if parens {
 		p.match(tok.RParen)
 	}
 	body := p.statement()
-	return &ast.ForIn{Var: ast.Ident{Name: id, Pos: pos}, E: expr, Body: body}
+	return &ast.ForIn{Index: index, Var: v, E: expr, Body: body}
+}
+
+func (p *parser) forInVar() ast.Ident {
+	id := p.Text
+	p.final[id] = disqualified
+	pos := p.Pos
+	p.matchIdent()
+	return ast.Ident{Name: id, Pos: pos}
 }
 
 func (p *parser) forClassic() *ast.For {
@@ -296,6 +296,8 @@ func TestParseStatements(t *testing.T) {
 	test("for x in ob\nstmt", "ForIn(x ob stmt)")
 	test("for x in ob { stmt }", "ForIn(x ob stmt)")
 	test("for (x in ob) stmt", "ForIn(x ob stmt)")
+	test("for i, x in ob\nstmt", "ForIn(i,x ob stmt)")
+	test("for (i, x in ob) stmt", "ForIn(i,x ob stmt)")
 
 	// for
 	test("for (;;) stmt", "For(; ; \n stmt)")
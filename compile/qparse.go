@@ -4,6 +4,7 @@
 package compile
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/apmckinlay/gsuneido/compile/ast"
@@ -39,13 +40,75 @@ type Rename struct {
 	To   string
 }
 
+// SummarizeOps are the aggregate operations supported by summarize
+var SummarizeOps = []string{"count", "total", "average", "min", "max", "list"}
+
+// ParseRequest parses a database request e.g. create, ensure, alter.
+// It panics if there is an error.
 func ParseRequest(src string) *Request {
+	rq, err := CheckRequest(src)
+	if err != nil {
+		panic(err.Error())
+	}
+	return rq
+}
+
+// CheckRequest is like ParseRequest
+// but returns a *ParseError or a *SchemaError rather than panicking
+func CheckRequest(src string) (rq *Request, err error) {
 	p := NewQueryParser(src)
-	result := p.request()
+	defer func() {
+		if e := recover(); e != nil {
+			err = p.requestError(e)
+		}
+	}()
+	rq = p.request()
 	if p.Token != tok.Eof {
 		p.error("did not parse all input")
 	}
-	return result
+	return rq, nil
+}
+
+// ParseError is a syntax error in a request.
+// Pos is the position in the source of the offending token.
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return "syntax error @" + strconv.Itoa(e.Pos) + " " + e.Msg
+}
+
+// SchemaError is a request that parses but has an invalid schema
+// e.g. a missing key or an index on a nonexistent column.
+// Columns are the offending columns, if any.
+type SchemaError struct {
+	Columns []string
+	Msg     string
+}
+
+func (e *SchemaError) Error() string {
+	return e.Msg
+}
+
+// requestError converts a panic from parsing a request to an error
+func (p *qparser) requestError(e interface{}) error {
+	switch e := e.(type) {
+	case *SchemaError:
+		return e
+	case string:
+		// from parserBase.errorAt
+		if s := strings.TrimPrefix(e, "syntax error @"); s != e {
+			if i := strings.IndexByte(s, ' '); i != -1 {
+				if pos, err := strconv.Atoi(s[:i]); err == nil {
+					return &ParseError{Pos: pos, Msg: s[i+1:]}
+				}
+			}
+		}
+		return &ParseError{Pos: int(p.Item.Pos), Msg: e}
+	}
+	panic(e)
 }
 
 func (p *qparser) request() *Request {
@@ -64,7 +127,7 @@ func (p *qparser) request() *Request {
 		return p.alter()
 	//TODO: View, Sview
 	default:
-		panic("invalid request")
+		panic(p.error("invalid request"))
 	}
 }
 
@@ -88,7 +151,7 @@ func (p *qparser) alter() *Request {
 		return &Request{Action: "alter", SubAction: "rename",
 			Schema: p.schema2(table, false), Renames: p.renames()}
 	default:
-		panic("invalid request")
+		panic(p.error("invalid request"))
 	}
 }
 
@@ -124,12 +187,17 @@ func (p *qparser) columns(full bool) (columns, derived []string) {
 			columns = append(columns, "-")
 		} else {
 			col := p.matchIdent()
+			if str.List(columns).Has(col) || str.List(derived).Has(col) {
+				panic(&SchemaError{Columns: []string{col},
+					Msg: "duplicate column: " + col})
+			}
 			if str.Capitalized(col) {
 				derived = append(derived, col)
 			} else if strings.HasSuffix(col, "_lower!") {
 				if full &&
 					!str.List(columns).Has(strings.TrimSuffix(col, "_lower!")) {
-					panic("_lower! base column not found")
+					panic(&SchemaError{Columns: []string{col},
+						Msg: "_lower! base column not found"})
 				}
 				derived = append(derived, col)
 			} else {
@@ -147,11 +215,17 @@ func (p *qparser) indexes(columns, derived []string, full bool) []Index {
 	hasKey := false
 	indexes := make([]Index, 0, 4)
 	for ix := p.index(columns, derived, full); ix != nil; ix = p.index(columns, derived, full) {
+		for i := range indexes {
+			if str.List(indexes[i].Columns).Equal(ix.Columns) {
+				panic(&SchemaError{Columns: ix.Columns,
+					Msg: "duplicate index: " + str.Join("(,)", ix.Columns...)})
+			}
+		}
 		indexes = append(indexes, *ix)
 		hasKey = hasKey || ix.Mode == 'k'
 	}
 	if full && !hasKey {
-		panic("key required")
+		panic(&SchemaError{Msg: "key required"})
 	}
 	return indexes
 }
@@ -164,13 +238,14 @@ func (p *qparser) index(columns, derived []string, full bool) *Index {
 	if p.Token == tok.Key {
 		mode = 'k'
 	}
+	pos := p.Pos
 	p.next()
 	if mode != 'k' && p.matchIf(tok.Unique) {
 		mode = 'u'
 	}
 	ixcols := p.indexColumns(columns, derived, full)
 	if mode != 'k' && len(ixcols) == 0 {
-		p.error("index columns must not be empty")
+		p.errorAt(pos, "index columns must not be empty")
 	}
 	ix := &Index{Columns: ixcols, Mode: mode}
 	ix.Fktable, ix.Fkcolumns, ix.Fkmode = p.foreignKey()
@@ -184,7 +259,8 @@ func (p *qparser) indexColumns(columns, derived []string, full bool) []string {
 		col := p.matchIdent()
 		if full && !str.List(columns).Has(col) &&
 			(!strings.HasSuffix(col, "_lower!") || !str.List(derived).Has(col)) {
-			p.error("invalid index column: " + col)
+			panic(&SchemaError{Columns: []string{col},
+				Msg: "invalid index column: " + col})
 		}
 		ixcols = append(ixcols, col)
 		p.matchIf(tok.Comma)
@@ -51,3 +51,70 @@ func TestQueryParserCreate(t *testing.T) {
 	xtest("create mytable (one,two,three_lower!) key(one)",
 		"_lower! base column not found")
 }
+
+func TestCheckRequest(t *testing.T) {
+	rq, err := CheckRequest("drop mytable")
+	assert.T(t).This(err).Is(nil)
+	assert.T(t).This(rq.String()).Is("drop mytable")
+
+	perr := func(qs string, pos int, msg string) {
+		t.Helper()
+		_, err := CheckRequest(qs)
+		pe, ok := err.(*ParseError)
+		assert.T(t).That(ok)
+		assert.T(t).This(pe.Pos).Is(pos)
+		assert.T(t).This(pe.Msg).Is(msg)
+	}
+	perr("drop", 4, "expecting identifier")
+	perr("rename one two", 11, "expecting To")
+	perr("drop mytable extra", 13, "did not parse all input")
+	perr("foo bar", 0, "invalid request")
+	perr("alter mytable foo", 14, "invalid request")
+	perr("create mytable (one) index() key(one)", 21,
+		"index columns must not be empty")
+	perr("create mytable (one, 123) key(one)", 21, "expecting identifier")
+	perr("ensure mytable index(one two", 28, "expecting identifier")
+
+	serr := func(qs string, cols []string, msg string) {
+		t.Helper()
+		_, err := CheckRequest(qs)
+		se, ok := err.(*SchemaError)
+		assert.T(t).That(ok)
+		assert.T(t).This(se.Columns).Is(cols)
+		assert.T(t).This(se.Msg).Is(msg)
+	}
+	serr("create mytable (one,two) index(one)", nil, "key required")
+	serr("create mytable (one,two) key(bar)", []string{"bar"},
+		"invalid index column: bar")
+	serr("create mytable (one,two_lower!) key(one)", []string{"two_lower!"},
+		"_lower! base column not found")
+	serr("create mytable (one,two,one) key(one)", []string{"one"},
+		"duplicate column: one")
+	serr("create mytable (one,two) key(one) index(two) key(one)",
+		[]string{"one"}, "duplicate index: (one)")
+	serr("ensure mytable index(one,two) index(one,two)",
+		[]string{"one", "two"}, "duplicate index: (one,two)")
+}
+
+func TestParseWhere(t *testing.T) {
+	test := func(src string, expected string) {
+		t.Helper()
+		assert.T(t).This(ParseWhere(src).String()).Like(expected)
+	}
+	test("a > 5", "Binary(Gt a 5)")
+	test("a is 'x'", `Binary(Is a "x")`)
+	test("a > 5 and (b < 3 or not c)",
+		"Nary(And Binary(Gt a 5) Unary(LParen Nary(Or Binary(Lt b 3) Unary(Not c))))")
+	test("(a is b)", "Unary(LParen Binary(Is a b))")
+	test("a in (1, 2)", "In(a [1 2])")
+	test("F(a)", "Call(F a)")
+	test("true", "true")
+
+	assert.T(t).This(func() { ParseWhere("a >") }).Panics("syntax error")
+	assert.T(t).This(func() { ParseWhere("a > 5 b") }).
+		Panics("did not parse all input")
+	assert.T(t).This(func() { ParseWhere("a + 5") }).Panics("must be boolean")
+	assert.T(t).This(func() { ParseWhere("a = 5") }).Panics("must be boolean")
+	assert.T(t).This(func() { ParseWhere("123") }).Panics("must be boolean")
+	assert.T(t).This(func() { ParseWhere("(a $ b)") }).Panics("must be boolean")
+}
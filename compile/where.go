@@ -0,0 +1,51 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package compile
+
+import (
+	"github.com/apmckinlay/gsuneido/compile/ast"
+	tok "github.com/apmckinlay/gsuneido/lexer/tokens"
+	. "github.com/apmckinlay/gsuneido/runtime"
+)
+
+// ParseWhere parses a single expression for use as a where condition
+// e.g. "a > 5 and b is 'x'".
+// The query parser does not handle expressions yet
+// so this uses the language expression parser.
+// It panics if there is a syntax error
+// or if the expression is clearly not boolean.
+func ParseWhere(src string) ast.Expr {
+	p := NewParser(src)
+	e := p.expr()
+	if p.Token != tok.Eof {
+		p.error("did not parse all input")
+	}
+	if !maybeBool(e) {
+		panic("where: expression must be boolean")
+	}
+	return e
+}
+
+// maybeBool returns false if an expression can not produce a boolean.
+// Identifiers, members, calls, etc. are unknown so they are allowed.
+func maybeBool(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.Constant:
+		return e.Val == True || e.Val == False
+	case *ast.Unary:
+		if e.Tok == tok.LParen {
+			return maybeBool(e.E)
+		}
+		return e.Tok == tok.Not
+	case *ast.Binary:
+		return tok.CompareStart < e.Tok && e.Tok < tok.CompareEnd
+	case *ast.Nary:
+		return e.Tok == tok.And || e.Tok == tok.Or
+	case *ast.Trinary:
+		return maybeBool(e.T) && maybeBool(e.F)
+	case *ast.RangeTo, *ast.RangeLen, *ast.Function, *ast.Block:
+		return false
+	}
+	return true
+}
@@ -5,7 +5,9 @@ package db19
 
 import (
 	"math/rand"
+	"sort"
 	"sync/atomic"
+	"time"
 
 	"github.com/apmckinlay/gsuneido/util/assert"
 	"github.com/apmckinlay/gsuneido/util/ints"
@@ -42,9 +44,11 @@ type Check struct {
 }
 
 type CkTran struct {
-	start    int
-	end      int
-	birth    int
+	start int
+	end   int
+	birth int
+	// started is the wall clock time the transaction started
+	started  time.Time
 	tables   map[string]*cktbl
 	conflict atomic.Value // string
 }
@@ -68,7 +72,7 @@ func (ck *Check) StartTran() *CkTran {
 	}
 	start := ck.next()
 	t := &CkTran{start: start, end: ints.MaxInt, birth: ck.clock,
-		tables: make(map[string]*cktbl)}
+		started: time.Now(), tables: make(map[string]*cktbl)}
 	ck.trans[start] = t
 	return t
 }
@@ -223,6 +227,15 @@ func (ck *Check) Abort(t *CkTran) bool {
 	return ck.abort(t.start, "explicit")
 }
 
+// Kill aborts the transaction with the given number (see Transactions).
+// It returns false if the transaction is not found or has already ended.
+func (ck *Check) Kill(tn int) bool {
+	if t, ok := ck.trans[tn]; !ok || t.isEnded() {
+		return false
+	}
+	return ck.abort(tn, "killed")
+}
+
 func (ck *Check) abort(tn int, reason string) bool {
 	trace("abort", tn)
 	t, ok := ck.trans[tn]
@@ -252,6 +265,11 @@ func (ck *Check) commit(ut *UpdateTran) []string {
 	if !ok {
 		return nil // it's gone, presumably aborted
 	}
+	// write ahead, before the client is told the commit succeeded
+	if err := ut.logCommit(); err != nil {
+		ck.abort(tn, err.Error())
+		return nil
+	}
 	t.end = ck.next()
 	if t.start == ck.oldest {
 		ck.oldest = ints.MaxInt // need to find the new oldest
@@ -295,6 +313,25 @@ func (ck *Check) cleanEnded() {
 	}
 }
 
+// TranInfo describes an outstanding update transaction
+type TranInfo struct {
+	Num   int
+	Start time.Time
+}
+
+// Transactions returns the outstanding (not ended) update transactions
+// oldest first
+func (ck *Check) Transactions() []TranInfo {
+	list := make([]TranInfo, 0, len(ck.trans))
+	for _, t := range ck.trans {
+		if !t.isEnded() {
+			list = append(list, TranInfo{Num: t.start, Start: t.started})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Num < list[j].Num })
+	return list
+}
+
 // MaxAge is the maximum number of ticks that a transaction can be outstanding.
 // Transactions are aborted if they exceed this limit.
 var MaxAge = 20
@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/apmckinlay/gsuneido/util/assert"
 )
@@ -47,6 +48,29 @@ func TestCheckLimit(t *testing.T) {
 	assert.T(t).That(ck.StartTran() == nil)
 }
 
+func TestCheckTransactions(t *testing.T) {
+	assert := assert.T(t)
+	ck := NewCheck()
+	assert.This(len(ck.Transactions())).Is(0)
+	before := time.Now()
+	t1 := &UpdateTran{ct: ck.StartTran()}
+	t2 := &UpdateTran{ct: ck.StartTran()}
+	t3 := &UpdateTran{ct: ck.StartTran()}
+	list := ck.Transactions()
+	assert.This(len(list)).Is(3)
+	assert.This(list[0].Num).Is(t1.num())
+	assert.This(list[1].Num).Is(t2.num())
+	for _, ti := range list {
+		assert.That(!ti.Start.Before(before))
+		assert.That(time.Since(ti.Start) < time.Minute)
+	}
+	ck.Commit(t1)
+	ck.Abort(t3.ct)
+	list = ck.Transactions()
+	assert.This(len(list)).Is(1)
+	assert.This(list[0].Num).Is(t2.num())
+}
+
 func TestCheckActions(t *testing.T) {
 	checkerAbortT1 = true
 	defer func() { checkerAbortT1 = false }()
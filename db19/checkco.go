@@ -44,6 +44,15 @@ type ckAbort struct {
 	t *CkTran
 }
 
+type ckTrans struct {
+	ret chan []TranInfo
+}
+
+type ckKill struct {
+	tn  int
+	ret chan bool
+}
+
 func (ck *CheckCo) StartTran() *CkTran {
 	ret := make(chan *CkTran, 1)
 	ck.c <- &ckStart{ret: ret}
@@ -80,6 +89,18 @@ func (ck *CheckCo) Abort(t *CkTran) bool {
 	return true
 }
 
+func (ck *CheckCo) Transactions() []TranInfo {
+	ret := make(chan []TranInfo, 1)
+	ck.c <- &ckTrans{ret: ret}
+	return <-ret
+}
+
+func (ck *CheckCo) Kill(tn int) bool {
+	ret := make(chan bool, 1)
+	ck.c <- &ckKill{tn: tn, ret: ret}
+	return <-ret
+}
+
 func (t *CkTran) Aborted() bool {
 	return t.conflict.Load() != nil
 }
@@ -129,6 +150,10 @@ func (ck *Check) dispatch(msg interface{}, mergeChan chan merge) {
 		ck.Write(msg.t, msg.table, msg.keys)
 	case *ckAbort:
 		ck.Abort(msg.t)
+	case *ckTrans:
+		msg.ret <- ck.Transactions()
+	case *ckKill:
+		msg.ret <- ck.Kill(msg.tn)
 	case *ckCommit:
 		result := ck.commit(msg.t)
 		// checking complete so we can send result and let client code continue
@@ -154,6 +179,8 @@ type Checker interface {
 	Write(t *CkTran, table string, keys []string) bool
 	Abort(t *CkTran) bool
 	Commit(t *UpdateTran) bool
+	Transactions() []TranInfo
+	Kill(tn int) bool
 	Stop()
 }
 
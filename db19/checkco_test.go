@@ -28,6 +28,16 @@ func TestCheckCoTimeout(t *testing.T) {
 	close(ck.c)
 }
 
+func TestCheckCoTransactions(t *testing.T) {
+	ck := StartCheckCo(nil, nil)
+	defer close(ck.c)
+	t1 := ck.StartTran()
+	ck.StartTran()
+	list := ck.Transactions()
+	assert.T(t).This(len(list)).Is(2)
+	assert.T(t).This(list[0].Num).Is(t1.start)
+}
+
 func TestCheckCoRandom(*testing.T) {
 	ck := StartCheckCo(nil, nil)
 	nThreads := 8
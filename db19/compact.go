@@ -13,13 +13,19 @@ import (
 	"github.com/apmckinlay/gsuneido/runtime"
 	"github.com/apmckinlay/gsuneido/util/assert"
 	"github.com/apmckinlay/gsuneido/util/cksum"
+	"github.com/apmckinlay/gsuneido/util/hacks"
 	"github.com/apmckinlay/gsuneido/util/sortlist"
+	"github.com/apmckinlay/gsuneido/util/str"
 )
 
 // Compact cleans up old records and index nodes that are no longer in use.
 // It does this by copying live data to a new database file.
-// In the process it concurrently does a full check of the database.
-func Compact(dbfile string) (ntables int, err error) {
+// In the process it concurrently does a full check of the database
+// and removes deleted columns.
+// Tables listed in skip are copied as is, without checking or squeezing,
+// which can save time on large tables that rarely change.
+// Their indexes are still rebuilt since they contain offsets.
+func Compact(dbfile string, skip ...string) (ntables int, err error) {
 	defer func() {
 		if e := recover(); e != nil {
 			err = fmt.Errorf("compact failed: %v", e)
@@ -35,7 +41,8 @@ func Compact(dbfile string) (ntables int, err error) {
 
 	state := src.GetState()
 	state.meta.ForEachSchema(func(sc *meta.Schema) {
-		compactTable(state, src, sc, dst, ics)
+		check := !str.List(skip).Has(sc.Table)
+		compactTable(state, src, sc, dst, ics, check)
 		ntables++
 	})
 	dst.GetState().Write(true)
@@ -57,8 +64,15 @@ func tmpdb() (*Database, string) {
 }
 
 func compactTable(state *DbState, src *Database, ts *meta.Schema, dst *Database,
-	ics *indexCheckers) {
+	ics *indexCheckers, check bool) {
 	info := state.meta.GetRoInfo(ts.Table)
+	cols := ts.Columns
+	squeeze := false
+	if check {
+		ts2 := squeezeSchema(ts)
+		squeeze = ts2 != ts
+		ts = ts2
+	}
 	before := dst.store.Size()
 	list := sortlist.NewUnsorted()
 	sum := uint64(0)
@@ -67,17 +81,94 @@ func compactTable(state *DbState, src *Database, ts *meta.Schema, dst *Database,
 		rec := src.store.Data(off)
 		size := runtime.RecLen(rec)
 		rec = rec[:size+cksum.Len]
-		cksum.MustCheck(rec)
+		if check {
+			cksum.MustCheck(rec)
+			if squeeze {
+				sq := Squeeze(runtime.Record(hacks.BStoS(rec[:size])), cols)
+				rec = make([]byte, len(sq)+cksum.Len)
+				copy(rec, sq)
+				cksum.Update(rec)
+			}
+		}
 		off2, buf := dst.store.Alloc(len(rec))
 		copy(buf, rec)
-		//TODO squeeze records when table has deleted fields
 		list.Add(off2)
 	})
 	list.Finish()
 	assert.This(count).Is(info.Nrows)
-	ics.checkOtherIndexes(info, count, sum) // concurrent
+	if check {
+		ics.checkOtherIndexes(info, count, sum) // concurrent
+	}
 	dataSize := dst.store.Size() - before
 	ov := buildIndexes(ts, list, dst.store, count) // same as load
 	ti := &meta.Info{Table: ts.Table, Nrows: count, Size: dataSize, Indexes: ov}
 	dst.LoadedTable(ts, ti)
 }
+
+// Squeeze returns the record without the deleted ("-") columns.
+// If there are no deleted columns it returns the record unchanged.
+func Squeeze(rec runtime.Record, cols []string) runtime.Record {
+	if !str.List(cols).Has("-") {
+		return rec
+	}
+	var b runtime.RecordBuilder
+	for i, n := 0, rec.Count(); i < n; i++ {
+		if i >= len(cols) || cols[i] != "-" {
+			b.AddRaw(rec.GetRaw(i))
+		}
+	}
+	return b.Build()
+}
+
+// squeezeSchema returns a copy of the schema without the deleted columns.
+// If there are no deleted columns it returns the schema unchanged.
+// NOTE: the indexes still need their Ixspecs updated (e.g. by buildIndexes)
+func squeezeSchema(ts *meta.Schema) *meta.Schema {
+	if !str.List(ts.Columns).Has("-") {
+		return ts
+	}
+	ts2 := *ts
+	ts2.Columns = str.List(ts.Columns).Without("-")
+	ts2.Indexes = append(ts.Indexes[:0:0], ts.Indexes...)
+	return &ts2
+}
+
+// RemoveDeletedColumns rewrites a table without its deleted columns.
+// The old records are not removed from the database file,
+// that requires Compact.
+// There must not be any concurrent updates to the table
+// and its changes must already be persisted.
+func RemoveDeletedColumns(db *Database, table string) {
+	state := db.GetState()
+	ts := state.meta.GetRoSchema(table)
+	if ts == nil {
+		panic("table not found: " + table)
+	}
+	ts2 := squeezeSchema(ts)
+	if ts2 == ts {
+		return // no deleted columns
+	}
+	info := state.meta.GetRoInfo(table)
+	for _, ov := range info.Indexes {
+		if ov.Modified() {
+			panic("RemoveDeletedColumns: " + table + " has unpersisted changes")
+		}
+	}
+	list := sortlist.NewUnsorted()
+	size := uint64(0)
+	count := info.Indexes[0].Check(func(off uint64) {
+		rec := Squeeze(offToRecCk(db.store, off), ts.Columns)
+		off2, buf := db.store.Alloc(len(rec) + cksum.Len)
+		copy(buf, rec)
+		cksum.Update(buf)
+		size += uint64(len(rec))
+		list.Add(off2)
+	})
+	list.Finish()
+	ov := buildIndexes(ts2, list, db.store, count)
+	for i := range ov {
+		ov[i].SetIxspec(&ts2.Indexes[i].Ixspec)
+	}
+	db.LoadedTable(ts2, &meta.Info{Table: table, Nrows: count, Size: size,
+		Indexes: ov})
+}
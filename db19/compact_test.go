@@ -0,0 +1,163 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package db19
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/apmckinlay/gsuneido/db19/index"
+	"github.com/apmckinlay/gsuneido/db19/index/ixspec"
+	"github.com/apmckinlay/gsuneido/db19/meta"
+	"github.com/apmckinlay/gsuneido/db19/meta/schema"
+	"github.com/apmckinlay/gsuneido/db19/stor"
+	rt "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+	"github.com/apmckinlay/gsuneido/util/cksum"
+)
+
+func TestCompactSkip(t *testing.T) {
+	defer os.Remove("tmp.db")
+	defer os.Remove("tmp.db.bak")
+	db := createDb()
+	addTable(db, "bigtable")
+	db.ck = NewCheck()
+	for _, table := range []string{"mytable", "bigtable"} {
+		ut := db.NewUpdateTran()
+		ut.Output(table, mkrec("a", "one"))
+		ut.Output(table, mkrec("b", "two"))
+		commitSync(db, ut)
+	}
+	db.Persist(&execPersistSingle{}, true)
+	// corrupt the second (non-key) field of the records in a table
+	corrupt := func(table string) {
+		db.GetState().meta.GetRoInfo(table).Indexes[0].Check(func(off uint64) {
+			buf := db.store.Data(off)
+			fld := rt.Record(buf[:rt.RecLen(buf)]).GetRaw(1)
+			buf[bytes.Index(buf, []byte(fld))+1] ^= 1
+		})
+	}
+	corrupt("bigtable")
+	db.Close()
+
+	// bigtable is not checked
+	n, err := Compact("tmp.db", "bigtable")
+	assert.T(t).This(err).Is(nil)
+	assert.T(t).This(n).Is(2)
+	db, err = openDatabase("tmp.db", stor.UPDATE, false) // no quick check
+	ck(err)
+	for _, table := range []string{"mytable", "bigtable"} {
+		assert.T(t).This(tableRecs(db, table)).Is(2)
+	}
+	assert.T(t).This(tableCksumErrors(db, "bigtable")).Is(2) // copied as is
+
+	// mytable is checked
+	corrupt("mytable")
+	db.Close()
+	_, err = Compact("tmp.db", "bigtable")
+	assert.T(t).This(err.Error()).Like("compact failed: checksum error")
+}
+
+func TestSqueeze(t *testing.T) {
+	rec := mkrec("a", "b", "c", "d")
+	assert.T(t).This(Squeeze(rec, []string{"one", "two", "three", "four"})).
+		Is(rec)
+	assert.T(t).This(Squeeze(rec, []string{"one", "-", "three", "-"})).
+		Is(mkrec("a", "c"))
+	assert.T(t).This(Squeeze(rec, []string{"-", "two"})).
+		Is(mkrec("b", "c", "d"))
+}
+
+func TestRemoveDeletedColumns(t *testing.T) {
+	defer os.Remove("tmp.db")
+	defer os.Remove("tmp.db.bak")
+	db := createDb()
+	addTable(db, "deltable", "one", "-", "three")
+	db.ck = NewCheck()
+	ut := db.NewUpdateTran()
+	ut.Output("deltable", mkrec("a", "deleted", "one"))
+	ut.Output("deltable", mkrec("b", "deleted", "two"))
+	commitSync(db, ut)
+	assert.T(t).This(func() { RemoveDeletedColumns(db, "deltable") }).
+		Panics("unpersisted changes")
+	db.Persist(&execPersistSingle{}, false)
+	size := db.GetState().meta.GetRoInfo("deltable").Size
+
+	RemoveDeletedColumns(db, "deltable")
+	meta := db.GetState().meta
+	assert.T(t).This(meta.GetRoSchema("deltable").Columns).
+		Is([]string{"one", "three"})
+	info := meta.GetRoInfo("deltable")
+	assert.T(t).This(info.Nrows).Is(2)
+	assert.T(t).That(info.Size < size)
+	assert.T(t).This(tableData(db, "deltable")).
+		Is([]rt.Record{mkrec("a", "one"), mkrec("b", "two")})
+
+	// Compact also removes deleted columns
+	addTable(db, "deltable2", "one", "-", "three")
+	ut = db.NewUpdateTran()
+	ut.Output("deltable2", mkrec("c", "deleted", "three"))
+	commitSync(db, ut)
+	db.Persist(&execPersistSingle{}, true)
+	db.Close()
+	_, err := Compact("tmp.db")
+	ck(err)
+	db, err = OpenDatabase("tmp.db")
+	ck(err)
+	defer db.Close()
+	assert.T(t).This(tableData(db, "deltable")).
+		Is([]rt.Record{mkrec("a", "one"), mkrec("b", "two")})
+	assert.T(t).This(tableData(db, "deltable2")).
+		Is([]rt.Record{mkrec("c", "three")})
+	assert.T(t).This(db.GetState().meta.GetRoSchema("deltable2").Columns).
+		Is([]string{"one", "three"})
+}
+
+func tableData(db *Database, table string) []rt.Record {
+	var recs []rt.Record
+	db.GetState().meta.GetRoInfo(table).Indexes[0].Check(func(off uint64) {
+		recs = append(recs, offToRecCk(db.store, off))
+	})
+	return recs
+}
+
+func addTable(db *Database, table string, cols ...string) {
+	if len(cols) == 0 {
+		cols = []string{"one", "two"}
+	}
+	is := ixspec.T{Fields: []int{0}}
+	ts := &meta.Schema{Schema: schema.Schema{
+		Table:   table,
+		Columns: cols,
+		Indexes: []schema.Index{{Columns: []string{"one"}, Mode: 'k', Ixspec: is}},
+	}}
+	ov := index.NewOverlay(db.store, &is)
+	ov.Save()
+	db.LoadedTable(ts, &meta.Info{Table: table, Indexes: []*index.Overlay{ov}})
+}
+
+// commitSync commits an update transaction synchronously
+func commitSync(db *Database, ut *UpdateTran) {
+	tables := db.ck.(*Check).commit(ut)
+	ut.commit()
+	merges := &mergeList{}
+	merges.add(tables)
+	db.Merge(mergeSingle, merges)
+}
+
+func tableRecs(db *Database, table string) int {
+	return db.GetState().meta.GetRoInfo(table).Nrows
+}
+
+func tableCksumErrors(db *Database, table string) int {
+	n := 0
+	db.GetState().meta.GetRoInfo(table).Indexes[0].Check(func(off uint64) {
+		buf := db.store.Data(off)
+		if !cksum.Check(buf[:rt.RecLen(buf)+cksum.Len]) {
+			n++
+		}
+	})
+	return n
+}
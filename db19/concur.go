@@ -137,6 +137,8 @@ func (em *execMulti) worker() {
 type mergeList struct {
 	tn      []tableCount
 	results []meta.MergeUpdate
+	// ncommits is the number of commits (merge's) added
+	ncommits int
 }
 
 type tableCount struct {
@@ -145,6 +147,7 @@ type tableCount struct {
 }
 
 func (ml *mergeList) add(m merge) {
+	ml.ncommits++
 outer:
 	for _, table := range m {
 		for i := range ml.tn {
@@ -174,6 +177,7 @@ func (ml *mergeList) drain(mergeChan chan merge) {
 func (ml *mergeList) reset() {
 	ml.tn = ml.tn[:0]
 	ml.results = ml.results[:0]
+	ml.ncommits = 0
 }
 
 // ------------------------------------------------------------------
@@ -4,11 +4,15 @@
 package db19
 
 import (
+	"os"
+
 	"github.com/apmckinlay/gsuneido/db19/index/comp"
 	"github.com/apmckinlay/gsuneido/db19/index/fbtree"
 	"github.com/apmckinlay/gsuneido/db19/index/ixspec"
 	"github.com/apmckinlay/gsuneido/db19/meta"
+	"github.com/apmckinlay/gsuneido/db19/meta/schema"
 	"github.com/apmckinlay/gsuneido/db19/stor"
+	"github.com/apmckinlay/gsuneido/options"
 	rt "github.com/apmckinlay/gsuneido/runtime"
 	"github.com/apmckinlay/gsuneido/util/cksum"
 	"github.com/apmckinlay/gsuneido/util/hacks"
@@ -23,6 +27,10 @@ type Database struct {
 	state stateHolder
 
 	ck Checker
+
+	// ilog is the optional intent log, see StartIntentLog
+	ilog     *intentLog
+	ilogFile string
 }
 
 const magic = "gsndo001"
@@ -94,6 +102,14 @@ func (db *Database) LoadedTable(ts *meta.Schema, ti *meta.Info) {
 	})
 }
 
+// Schema returns the schema for a table, or nil if it doesn't exist
+func (db *Database) Schema(table string) *schema.Schema {
+	if ts := db.GetState().meta.GetRoSchema(table); ts != nil {
+		return &ts.Schema
+	}
+	return nil
+}
+
 func (db *Database) DropTable(table string) bool {
 	result := false
 	db.UpdateState(func(state *DbState) {
@@ -105,6 +121,11 @@ func (db *Database) DropTable(table string) bool {
 	return result
 }
 
+// StorStats returns the statistics for the database storage
+func (db *Database) StorStats() stor.Stats {
+	return db.store.Stats()
+}
+
 // Close closes the database store, writing the current size to the start.
 // NOTE: The state must already be written.
 func (db *Database) Close() {
@@ -124,6 +145,10 @@ func (db *Database) Close() {
 	}
 	db.store.Close()
 	db.store = nil
+	if db.ilog != nil {
+		db.ilog.close()
+		os.Remove(db.ilogFile)
+	}
 }
 
 //-------------------------------------------------------------------
@@ -145,6 +170,9 @@ func mkcmp(store *stor.Stor, is *ixspec.T) func(x, y uint64) int {
 }
 
 func offToRec(store *stor.Stor, off uint64) rt.Record {
+	if options.VerifyChecksums {
+		return offToRecCk(store, off)
+	}
 	buf := store.Data(off)
 	size := rt.RecLen(buf)
 	return rt.Record(hacks.BStoS(buf[:size]))
@@ -7,7 +7,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/apmckinlay/gsuneido/db19/stor"
+	"github.com/apmckinlay/gsuneido/options"
 	"github.com/apmckinlay/gsuneido/util/assert"
+	"github.com/apmckinlay/gsuneido/util/cksum"
 )
 
 func TestDatabaseDropTable(t *testing.T) {
@@ -17,3 +20,16 @@ func TestDatabaseDropTable(t *testing.T) {
 	assert.T(t).That(db.DropTable("mytable"))
 	assert.T(t).That(!db.DropTable("mytable"))
 }
+
+func TestVerifyChecksums(t *testing.T) {
+	store := stor.HeapStor(8 * 1024)
+	rec := mkrec("hello", "world")
+	off, buf := store.Alloc(len(rec) + cksum.Len)
+	copy(buf, rec)
+	cksum.Update(buf)
+	buf[len(rec)-1] ^= 0xff                          // corrupt the data (not the header)
+	assert.T(t).This(offToRec(store, off)).Isnt(rec) // not checked by default
+	options.VerifyChecksums = true
+	defer func() { options.VerifyChecksums = false }()
+	assert.T(t).This(func() { offToRec(store, off) }).Panics("checksum error")
+}
@@ -11,6 +11,7 @@ import (
 	"math"
 	"os"
 	"runtime"
+	"strconv"
 	"sync"
 	"sync/atomic"
 
@@ -18,19 +19,46 @@ import (
 	"github.com/apmckinlay/gsuneido/db19/meta"
 	"github.com/apmckinlay/gsuneido/db19/stor"
 	"github.com/apmckinlay/gsuneido/util/assert"
+	"github.com/apmckinlay/gsuneido/util/cksum"
 	"github.com/apmckinlay/gsuneido/util/ints"
 )
 
+// Progress is an optional callback for dump and load.
+// It is called periodically with the table name
+// and the number of records processed so far in that table,
+// and at the end of each table with its total
+// (unless the periodic call already reported it).
+// It is always called from the goroutine doing the dump or load.
+type Progress func(table string, nrecs int)
+
+// progressInterval is how many records between Progress calls
+const progressInterval = 10000
+
+func getProgress(progress []Progress) Progress {
+	if len(progress) == 0 || progress[0] == nil {
+		return func(string, int) {}
+	}
+	return progress[0]
+}
+
+// finalProgress reports the total for a table
+// unless it was already reported by the periodic call
+func finalProgress(progress Progress, table string, nrecs int) {
+	if nrecs == 0 || nrecs%progressInterval != 0 {
+		progress(table, nrecs)
+	}
+}
+
 // DumpDatabase exports a dumped database to a file.
 // In the process it concurrently does a full check of the database.
-func DumpDatabase(dbfile, to string) (ntables int, err error) {
+func DumpDatabase(dbfile, to string, progress ...Progress) (ntables int, err error) {
 	db, err := openDatabase(dbfile, stor.READ, false)
 	ck(err)
 	defer db.Close()
-	return db.Dump(to)
+	return db.Dump(to, progress...)
 }
 
-func (db *Database) Dump(to string) (ntables int, err error) {
+func (db *Database) Dump(to string, progress ...Progress) (ntables int, err error) {
 	defer func() {
 		if e := recover(); e != nil {
 			err = fmt.Errorf("dump failed: %v", e)
@@ -42,9 +70,10 @@ func (db *Database) Dump(to string) (ntables int, err error) {
 	ics := newIndexCheckers()
 	defer ics.finish()
 
+	pf := getProgress(progress)
 	state := db.GetState()
 	state.meta.ForEachSchema(func(sc *meta.Schema) {
-		dumpTable(db, sc, true, w, ics)
+		dumpTable(db, sc, true, w, ics, pf)
 		ntables++
 	})
 	ck(w.Flush())
@@ -56,14 +85,14 @@ func (db *Database) Dump(to string) (ntables int, err error) {
 
 // DumpTable exports a dumped table to a file.
 // It returns the number of records dumped or panics on error.
-func DumpTable(dbfile, table, to string) (nrecs int, err error) {
+func DumpTable(dbfile, table, to string, progress ...Progress) (nrecs int, err error) {
 	db, err := openDatabase(dbfile, stor.READ, false)
 	ck(err)
 	defer db.Close()
-	return db.DumpTable(table, to)
+	return db.DumpTable(table, to, progress...)
 }
 
-func (db *Database) DumpTable(table, to string) (nrecs int, err error) {
+func (db *Database) DumpTable(table, to string, progress ...Progress) (nrecs int, err error) {
 	defer func() {
 		if e := recover(); e != nil {
 			err = fmt.Errorf("dump failed: %v", e)
@@ -80,7 +109,7 @@ func (db *Database) DumpTable(table, to string) (nrecs int, err error) {
 	if schema == nil {
 		return 0, errors.New("dump failed: can't find " + table)
 	}
-	nrecs = dumpTable(db, schema, false, w, ics)
+	nrecs = dumpTable(db, schema, false, w, ics, getProgress(progress))
 	ck(w.Flush())
 	f.Close()
 	ics.finish()
@@ -89,16 +118,22 @@ func (db *Database) DumpTable(table, to string) (nrecs int, err error) {
 
 }
 
+// dumpVersion is the current version of the dump file format.
+// It is written in the header line e.g. "Suneido dump 3"
+// and checked by load.
+// Version 3 adds a checksum after the records of each table.
+const dumpVersion = 3
+
 func dumpOpen() (*os.File, *bufio.Writer) {
 	f, err := ioutil.TempFile(".", "gs*.tmp")
 	ck(err)
 	w := bufio.NewWriter(f)
-	w.WriteString("Suneido dump 2\n")
+	w.WriteString("Suneido dump " + strconv.Itoa(dumpVersion) + "\n")
 	return f, w
 }
 
 func dumpTable(db *Database, schema *meta.Schema, multi bool, w *bufio.Writer,
-	ics *indexCheckers) int {
+	ics *indexCheckers, progress Progress) int {
 	state := db.GetState()
 	w.WriteString("====== ")
 	if multi {
@@ -107,13 +142,21 @@ func dumpTable(db *Database, schema *meta.Schema, multi bool, w *bufio.Writer,
 	w.WriteString(schema.String() + "\n")
 	info := state.meta.GetRoInfo(schema.Table)
 	sum := uint64(0)
+	cs := uint32(0)
+	n := 0
 	count := info.Indexes[0].Check(func(off uint64) {
 		sum += off                       // addition so order doesn't matter
 		rec := offToRecCk(db.store, off) // verify data checksums
 		writeInt(w, len(rec))
 		w.WriteString(string(rec))
+		cs = cksum.Extend(cs, db.store.Data(off)[:len(rec)])
+		if n++; n%progressInterval == 0 {
+			progress(schema.Table, n)
+		}
 	})
+	finalProgress(progress, schema.Table, count)
 	writeInt(w, 0) // end of table records
+	writeInt(w, int(cs))
 	assert.This(count).Is(info.Nrows)
 	ics.checkOtherIndexes(info, count, sum) // concurrent
 	return count
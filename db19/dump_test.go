@@ -4,8 +4,13 @@
 package db19
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"sort"
+	"strconv"
 	"testing"
 	"time"
 
@@ -33,3 +38,148 @@ func TestDumpDatabase(t *testing.T) {
 	assert.T(t).This(err).Is(nil)
 	fmt.Println("dumped", n, "tables in", time.Since(start).Round(time.Millisecond))
 }
+
+func TestDumpVersion(t *testing.T) {
+	defer os.Remove("tmp.db")
+	defer os.Remove("tmp.su")
+	defer os.Remove("tmp2.db")
+	defer os.Remove("tmp2.db.bak")
+	dumpTestDb()
+	assert.T(t).This(LoadDatabase("tmp.su", "tmp2.db")).Is(2)
+
+	header := func(h string) {
+		data, err := ioutil.ReadFile("tmp.su")
+		ck(err)
+		i := bytes.IndexByte(data, '\n')
+		ck(ioutil.WriteFile("tmp.su", append([]byte(h), data[i:]...), 0644))
+	}
+	header("Suneido dump 99")
+	assert.T(t).This(func() { LoadDatabase("tmp.su", "tmp2.db") }).
+		Panics("unsupported dump version 99")
+	header("Suneido dump 1")
+	assert.T(t).This(func() { LoadDatabase("tmp.su", "tmp2.db") }).
+		Panics("unsupported dump version 1")
+	header("Suneido dump x")
+	assert.T(t).This(func() { LoadDatabase("tmp.su", "tmp2.db") }).
+		Panics("not a valid dump file")
+	header("Suneido dump " + strconv.Itoa(dumpVersion))
+	assert.T(t).This(LoadDatabase("tmp.su", "tmp2.db")).Is(2)
+}
+
+func TestLoadDumpVersion2(t *testing.T) {
+	defer os.Remove("tmp.su")
+	defer os.Remove("tmp2.db")
+	defer os.Remove("tmp2.db.bak")
+	// version 2 dumps do not have a checksum after each table's records
+	f, err := os.Create("tmp.su")
+	ck(err)
+	w := bufio.NewWriter(f)
+	w.WriteString("Suneido dump 2\n")
+	table := func(schema string, recs ...string) {
+		w.WriteString("====== " + schema + "\n")
+		for _, s := range recs {
+			rec := mkrec(s, "x")
+			writeInt(w, len(rec))
+			w.WriteString(string(rec))
+		}
+		writeInt(w, 0)
+	}
+	table("mytable (one,two) key(one)", "a", "b")
+	table("bigtable (one,two) key(one)", "c", "d", "e")
+	ck(w.Flush())
+	f.Close()
+
+	nrows := func(table string) int {
+		db, err := OpenDatabaseRead("tmp2.db")
+		ck(err)
+		defer db.Close()
+		return db.GetState().meta.GetRoInfo(table).Nrows
+	}
+	assert.T(t).This(LoadDatabase("tmp.su", "tmp2.db")).Is(2)
+	assert.T(t).This(nrows("mytable")).Is(2)
+	assert.T(t).This(nrows("bigtable")).Is(3)
+
+	os.Remove("tmp2.db")
+	assert.T(t).This(LoadTableFrom("tmp.su", "bigtable", "tmp2.db")).Is(3)
+	assert.T(t).This(nrows("bigtable")).Is(3)
+}
+
+func TestDumpChecksum(t *testing.T) {
+	defer os.Remove("tmp.db")
+	defer os.Remove("tmp.su")
+	defer os.Remove("tmp2.db")
+	defer os.Remove("tmp2.db.bak")
+	dumpTestDb()
+	data, err := ioutil.ReadFile("tmp.su")
+	ck(err)
+	i := bytes.Index(data, []byte("three"))
+	data[i] = 'T'
+	ck(ioutil.WriteFile("tmp.su", data, 0644))
+	assert.T(t).This(func() { LoadDatabase("tmp.su", "tmp2.db") }).
+		Panics("dump corrupted: bigtable")
+	assert.T(t).This(LoadTableFrom("tmp.su", "mytable", "tmp2.db")).Is(2)
+	assert.T(t).This(func() { LoadTableFrom("tmp.su", "bigtable", "tmp2.db") }).
+		Panics("dump corrupted: bigtable")
+}
+
+func TestDumpLoadProgress(t *testing.T) {
+	defer os.Remove("tmp.db")
+	defer os.Remove("tmp.su")
+	defer os.Remove("tmp2.db")
+	defer os.Remove("tmp2.db.bak")
+	db := createDb()
+	addTable(db, "bigtable")
+	addTable(db, "exacttable")
+	db.ck = NewCheck()
+	ut := db.NewUpdateTran()
+	ut.Output("mytable", mkrec("a", "one"))
+	ut.Output("mytable", mkrec("b", "two"))
+	commitSync(db, ut)
+	fill := func(table string, n int) {
+		for i := 0; i < n; i += 100 {
+			ut := db.NewUpdateTran()
+			for j := i; j < i+100 && j < n; j++ {
+				ut.Output(table, mkrec(strconv.Itoa(j), "x"))
+			}
+			commitSync(db, ut)
+		}
+	}
+	fill("bigtable", progressInterval+5)
+	fill("exacttable", progressInterval) // total only reported once
+	db.Persist(&execPersistSingle{}, true)
+
+	var calls []string
+	progress := func(table string, nrecs int) {
+		calls = append(calls, table+" "+strconv.Itoa(nrecs))
+	}
+	expected := []string{"bigtable 10000", "bigtable 10005",
+		"exacttable 10000", "mytable 2"}
+	_, err := db.Dump("tmp.su", progress)
+	ck(err)
+	sort.Strings(calls) // table order isn't defined
+	assert.T(t).This(calls).Is(expected)
+
+	calls = nil
+	assert.T(t).This(LoadDatabase("tmp.su", "tmp2.db", progress)).Is(3)
+	sort.Strings(calls)
+	assert.T(t).This(calls).Is(expected)
+
+	calls = nil
+	LoadTableFrom("tmp.su", "mytable", "tmp2.db", progress)
+	assert.T(t).This(calls).Is([]string{"mytable 2"})
+}
+
+// dumpTestDb creates tmp.db with two tables and dumps it to tmp.su
+func dumpTestDb() {
+	db := createDb()
+	addTable(db, "bigtable")
+	db.ck = NewCheck()
+	ut := db.NewUpdateTran()
+	ut.Output("mytable", mkrec("a", "one"))
+	ut.Output("mytable", mkrec("b", "two"))
+	ut.Output("bigtable", mkrec("c", "three"))
+	commitSync(db, ut)
+	db.Persist(&execPersistSingle{}, true)
+	_, err := db.Dump("tmp.su")
+	ck(err)
+}
@@ -0,0 +1,348 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package db19
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/apmckinlay/gsuneido/db19/stor"
+	rt "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/cksum"
+)
+
+// intentLog is an optional append-only log of committed update transactions.
+// Without it, a crash loses everything after the last persisted state
+// (see Repair). With it, RecoverLog can replay the transactions
+// that committed after the last state.
+//
+// The log file starts with logMagic, followed by entries. Each entry is:
+//   - kind, one byte, logCommit or logPersist
+//   - the length of the data, four bytes
+//   - the data
+//   - a checksum of the kind, length, and data
+//
+// The data of a commit entry is the outputs of the transaction, each:
+//   - the length of the table name, two bytes
+//   - the table name
+//   - the length of the record, four bytes
+//   - the record
+//
+// The data of a persist entry is the offset of a state (eight bytes)
+// and the number of commit entries preceding it in the log (eight bytes)
+// that the state completely includes.
+// The log starts with a persist entry for the state at the time
+// so there is always a state to recover from.
+//
+// Commits are written to the log before the client is told they succeeded.
+// Entries are written with a single write to the file,
+// but they are not synced, so like the database itself
+// they survive the process crashing but not the operating system.
+// An incomplete entry (from crashing during a write) ends the log.
+type intentLog struct {
+	lock sync.Mutex
+	f    *os.File
+	// ncommits is the number of commit entries written to the log
+	ncommits int
+	// nmerged is the number of logged commits that have been merged
+	nmerged int
+	// err is set if a write fails,
+	// after which the log is unusable and commits will fail
+	err error
+}
+
+const logMagic = "gsndlog1"
+
+const (
+	logCommit  = 'c'
+	logPersist = 'p'
+)
+
+const logHdrLen = 1 + 4
+
+// newIntentLog creates (or truncates) an intent log file
+// and writes the initial persist entry for the state at off
+func newIntentLog(filename string, off uint64) (*intentLog, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	il := &intentLog{f: f}
+	if _, err = f.Write([]byte(logMagic)); err == nil {
+		err = il.persist(off, 0)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return il, nil
+}
+
+// logOutput is an output by an update transaction
+type logOutput struct {
+	table string
+	off   uint64
+	n     int
+}
+
+// commit writes a commit entry for the outputs of a transaction.
+// The records are read from the store.
+func (il *intentLog) commit(store *stor.Stor, outputs []logOutput) error {
+	n := 0
+	for _, o := range outputs {
+		n += 2 + len(o.table) + 4 + o.n
+	}
+	buf := make([]byte, logHdrLen+n+cksum.Len)
+	i := logHdrLen
+	for _, o := range outputs {
+		binary.BigEndian.PutUint16(buf[i:], uint16(len(o.table)))
+		i += 2
+		i += copy(buf[i:], o.table)
+		binary.BigEndian.PutUint32(buf[i:], uint32(o.n))
+		i += 4
+		i += copy(buf[i:], store.Data(o.off)[:o.n])
+	}
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	if err := il.write(logCommit, buf); err != nil {
+		return err
+	}
+	il.ncommits++
+	return nil
+}
+
+// merged records that n logged commits have been merged
+func (il *intentLog) merged(n int) {
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	il.nmerged += n
+}
+
+// complete returns the number of logged commits
+// and whether they have all been merged.
+// It must be called while holding the state lock (i.e. in UpdateState)
+// since a commit is logged before it is added to the state.
+func (il *intentLog) complete() (int, bool) {
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	return il.ncommits, il.ncommits == il.nmerged
+}
+
+// persist writes a persist entry for the state at off
+// which includes the first ncommits logged commits
+func (il *intentLog) persist(off uint64, ncommits int) error {
+	buf := make([]byte, logHdrLen+16+cksum.Len)
+	binary.BigEndian.PutUint64(buf[logHdrLen:], off)
+	binary.BigEndian.PutUint64(buf[logHdrLen+8:], uint64(ncommits))
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	return il.write(logPersist, buf)
+}
+
+// write fills in the header and checksum of an entry and writes it.
+// It must be called with the lock held.
+func (il *intentLog) write(kind byte, buf []byte) error {
+	if il.err != nil {
+		return il.err
+	}
+	buf[0] = kind
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(buf)-logHdrLen-cksum.Len))
+	cksum.Update(buf)
+	if _, err := il.f.Write(buf); err != nil {
+		il.err = fmt.Errorf("intent log: %w", err)
+		return il.err
+	}
+	return nil
+}
+
+func (il *intentLog) close() {
+	il.f.Close()
+}
+
+//-------------------------------------------------------------------
+
+// StartIntentLog persists the current state
+// and then starts logging committed update transactions to filename.
+// It must be called before StartConcur.
+// Close removes the log since the final state includes everything.
+func (db *Database) StartIntentLog(filename string) error {
+	off := db.Persist(&execPersistSingle{}, true)
+	il, err := newIntentLog(filename, off)
+	if err != nil {
+		return err
+	}
+	db.ilog = il
+	db.ilogFile = filename
+	return nil
+}
+
+// logCommit writes the outputs of the transaction to the intent log (if any)
+func (t *UpdateTran) logCommit() error {
+	if t.db == nil || t.db.ilog == nil {
+		return nil
+	}
+	return t.db.ilog.commit(t.db.store, t.outputs)
+}
+
+//-------------------------------------------------------------------
+
+type logCheckpoint struct {
+	off      uint64
+	ncommits int
+}
+
+type logRecord struct {
+	table string
+	rec   rt.Record
+}
+
+// readIntentLog returns the checkpoints and the commits in an intent log.
+// It stops at the first incomplete or corrupt entry.
+func readIntentLog(filename string) ([]logCheckpoint, [][]logRecord, error) {
+	buf, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(buf) < len(logMagic) || string(buf[:len(logMagic)]) != logMagic {
+		return nil, nil, errors.New("invalid intent log: " + filename)
+	}
+	var cps []logCheckpoint
+	var commits [][]logRecord
+	buf = buf[len(logMagic):]
+	for len(buf) >= logHdrLen {
+		n := logHdrLen + int(binary.BigEndian.Uint32(buf[1:])) + cksum.Len
+		if n > len(buf) || !cksum.Check(buf[:n]) {
+			break // incomplete or corrupt
+		}
+		data := buf[logHdrLen : n-cksum.Len]
+		switch buf[0] {
+		case logCommit:
+			commits = append(commits, logRecords(data))
+		case logPersist:
+			cps = append(cps, logCheckpoint{
+				off:      binary.BigEndian.Uint64(data),
+				ncommits: int(binary.BigEndian.Uint64(data[8:]))})
+		}
+		buf = buf[n:]
+	}
+	return cps, commits, nil
+}
+
+func logRecords(data []byte) []logRecord {
+	var recs []logRecord
+	for len(data) > 0 {
+		n := int(binary.BigEndian.Uint16(data))
+		table := string(data[2 : 2+n])
+		data = data[2+n:]
+		n = int(binary.BigEndian.Uint32(data))
+		recs = append(recs, logRecord{table: table, rec: rt.Record(data[4 : 4+n])})
+		data = data[4+n:]
+	}
+	return recs
+}
+
+// RecoverLog recovers a database after a crash using its intent log.
+// Rather than using the last valid state (like Repair)
+// it uses the last valid state recorded in the log,
+// truncates the database after it,
+// and then replays the transactions that committed after it.
+// The log is removed if recovery succeeds.
+// It returns the number of transactions replayed.
+func RecoverLog(dbfile, logfile string) (n int, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("recover failed: %v", e)
+		}
+	}()
+	cps, commits, err := readIntentLog(logfile)
+	if err != nil {
+		return 0, err
+	}
+	store, err := stor.MmapStor(dbfile, stor.READ)
+	if err != nil {
+		return 0, err
+	}
+	cp, ok := lastGoodCheckpoint(store, cps)
+	if !ok {
+		store.Close()
+		return 0, errors.New("recover failed - no valid logged states found")
+	}
+	if cp.off+uint64(stateLen) == store.Size() {
+		store.Close()
+		err = writeSize(dbfile, cp.off+uint64(stateLen))
+	} else {
+		err = truncate(dbfile, store, cp.off)
+	}
+	if err != nil {
+		return 0, err
+	}
+	db, err := OpenDatabase(dbfile)
+	if err != nil {
+		return 0, err
+	}
+	db.ck = NewCheck()
+	defer db.Close()
+	for _, recs := range commits[cp.ncommits:] {
+		replay(db, recs)
+		n++
+	}
+	db.Persist(&execPersistSingle{}, true)
+	return n, os.Remove(logfile)
+}
+
+// lastGoodCheckpoint returns the last checkpoint with a valid state
+func lastGoodCheckpoint(store *stor.Stor, cps []logCheckpoint) (logCheckpoint, bool) {
+	for i := len(cps) - 1; i >= 0; i-- {
+		if cps[i].off+uint64(stateLen) > store.Size() {
+			continue
+		}
+		if state := stateAt(store, cps[i].off); state != nil &&
+			checkState(state, "") == nil {
+			return cps[i], true
+		}
+	}
+	return logCheckpoint{}, false
+}
+
+func stateAt(store *stor.Stor, off uint64) (state *DbState) {
+	defer func() {
+		if e := recover(); e != nil {
+			state = nil
+		}
+	}()
+	state, _ = ReadState(store, off)
+	return state
+}
+
+// writeSize updates the size at the start of the database file
+func writeSize(dbfile string, size uint64) error {
+	f, err := os.OpenFile(dbfile, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, stor.SmallOffsetLen)
+	stor.WriteSmallOffset(buf, size)
+	if _, err = f.WriteAt(buf, int64(len(magic))); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// replay redoes the outputs of a logged transaction
+func replay(db *Database, recs []logRecord) {
+	ut := db.NewUpdateTran()
+	for _, r := range recs {
+		ut.Output(r.table, r.rec)
+	}
+	tables := db.ck.(*Check).commit(ut)
+	ut.commit()
+	merges := &mergeList{}
+	merges.add(tables)
+	db.Merge(mergeSingle, merges)
+}
@@ -0,0 +1,119 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package db19
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	rt "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestIntentLogRecover(t *testing.T) {
+	assert := assert.T(t).This
+	defer os.Remove("tmp.db")
+	defer os.Remove("tmp.db.bak")
+	defer os.Remove("tmp.log")
+	db := createDb()
+	db.ck = NewCheck()
+	ck(db.StartIntentLog("tmp.log"))
+	output := func(keys ...string) *UpdateTran {
+		ut := db.NewUpdateTran()
+		for _, k := range keys {
+			ut.Output("mytable", mkrec(k, "x"))
+		}
+		return ut
+	}
+	commitSync(db, output("a", "b"))
+	db.Persist(&execPersistSingle{}, false)
+	commitSync(db, output("c"))
+	commitSync(db, output("d", "e"))
+	output("f") // crash in the middle of this transaction
+	crash(db)
+	f, err := os.OpenFile("tmp.log", os.O_WRONLY|os.O_APPEND, 0)
+	ck(err)
+	f.Write([]byte{logCommit, 0, 0, 1}) // crash while writing the log
+	f.Close()
+
+	_, err = OpenDatabase("tmp.db")
+	assert(err).Isnt(nil)
+	n, err := RecoverLog("tmp.db", "tmp.log")
+	assert(err).Is(nil)
+	assert(n).Is(2)
+	_, err = os.Stat("tmp.log")
+	assert(os.IsNotExist(err)).Is(true)
+
+	db, err = OpenDatabase("tmp.db")
+	ck(err)
+	defer db.Close()
+	assert(tableData(db, "mytable")).Is([]rt.Record{mkrec("a", "x"),
+		mkrec("b", "x"), mkrec("c", "x"), mkrec("d", "x"), mkrec("e", "x")})
+	assert(tableRecs(db, "mytable")).Is(5)
+}
+
+func TestIntentLogCheckpoint(t *testing.T) {
+	assert := assert.T(t).This
+	defer os.Remove("tmp.db")
+	defer os.Remove("tmp.db.bak")
+	defer os.Remove("tmp.log")
+	db := createDb()
+	db.ck = NewCheck()
+	ck(db.StartIntentLog("tmp.log"))
+	ut := db.NewUpdateTran()
+	ut.Output("mytable", mkrec("a", "x"))
+	commitSync(db, ut)
+	off1 := db.Persist(&execPersistSingle{}, false)
+	ut = db.NewUpdateTran()
+	ut.Output("mytable", mkrec("b", "x"))
+	db.ck.(*Check).commit(ut)
+	ut.commit()
+	// not merged, so the state is not complete and is not logged
+	db.Persist(&execPersistSingle{}, false)
+	cps, commits, err := readIntentLog("tmp.log")
+	ck(err)
+	assert(len(cps)).Is(2)
+	assert(cps[1]).Is(logCheckpoint{off: off1, ncommits: 1})
+	assert(len(commits)).Is(2)
+	assert(commits[1]).Is([]logRecord{{table: "mytable", rec: mkrec("b", "x")}})
+
+	db.ilog.f.Close() // make writes to the log fail
+	ut = db.NewUpdateTran()
+	ut.Output("mytable", mkrec("c", "x"))
+	assert(func() { ut.Commit() }).Panics("aborted: intent log")
+
+	crash(db)
+	n, err := RecoverLog("tmp.db", "tmp.log")
+	assert(err).Is(nil)
+	assert(n).Is(1)
+	db, err = OpenDatabase("tmp.db")
+	ck(err)
+	defer db.Close()
+	assert(tableData(db, "mytable")).
+		Is([]rt.Record{mkrec("a", "x"), mkrec("b", "x")})
+}
+
+func TestIntentLogConcur(t *testing.T) {
+	assert := assert.T(t).This
+	defer os.Remove("tmp.db")
+	db := createDb()
+	ck(db.StartIntentLog("tmp.log"))
+	StartConcur(db, time.Hour)
+	ut := db.NewUpdateTran()
+	ut.Output("mytable", mkrec("a", "x"))
+	ut.Commit()
+	_, commits, err := readIntentLog("tmp.log")
+	ck(err)
+	assert(len(commits)).Is(1) // logged before Commit returns
+	db.Close()
+	_, err = os.Stat("tmp.log")
+	assert(os.IsNotExist(err)).Is(true) // removed by a clean Close
+}
+
+// crash closes the database files without writing a state or the size
+func crash(db *Database) {
+	db.store.Close()
+	db.ilog.close()
+}
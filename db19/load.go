@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/apmckinlay/gsuneido/compile"
@@ -19,27 +20,29 @@ import (
 	"github.com/apmckinlay/gsuneido/util/assert"
 	"github.com/apmckinlay/gsuneido/util/cksum"
 	"github.com/apmckinlay/gsuneido/util/sortlist"
+	"github.com/apmckinlay/gsuneido/util/str"
 )
 
 // LoadDatabase imports a dumped database from a file.
 // It returns the number of tables loaded or panics on error.
-func LoadDatabase(from, dbfile string) int {
+func LoadDatabase(from, dbfile string, progress ...Progress) int {
 	defer func() {
 		if e := recover(); e != nil {
 			panic("load failed: " + fmt.Sprint(e))
 		}
 	}()
-	f, r := open(from)
+	f, r, ver := open(from)
 	defer f.Close()
 	db, tmpfile := tmpdb()
 	defer func() { db.Close(); os.Remove(tmpfile) }()
+	pf := getProgress(progress)
 	nTables := 0
 	for ; ; nTables++ {
 		schema := readLinePrefixed(r, "====== ")
 		if schema == "" {
 			break
 		}
-		loadTable(db, r, schema)
+		loadTable(db, r, ver, schema, pf)
 		trace()
 		assert.That(nTables < 1010)
 	}
@@ -52,47 +55,130 @@ func LoadDatabase(from, dbfile string) int {
 
 // LoadTable imports a dumped table from a file.
 // It returns the number of records loaded or panics on error.
-func LoadTable(table, dbfile string) int {
+func LoadTable(table, dbfile string, progress ...Progress) int {
 	defer func() {
 		if e := recover(); e != nil {
 			panic("load failed: " + table + " " + fmt.Sprint(e))
 		}
 	}()
+	db := openOrCreate(dbfile)
+	defer db.Close()
+	nrecs := db.loadTable(table, getProgress(progress))
+	db.GetState().Write(true)
+	return nrecs
+}
+
+// LoadTable imports a dumped table (from table.su) into an open database,
+// replacing any existing table with the same name.
+// The new state will be written by the next persist.
+// The load bypasses the checker so it is refused
+// if there are outstanding update transactions.
+// It returns the number of records loaded or panics on error.
+func (db *Database) LoadTable(table string, progress ...Progress) int {
+	defer func() {
+		if e := recover(); e != nil {
+			panic("load failed: " + table + " " + fmt.Sprint(e))
+		}
+	}()
+	if len(db.Transactions()) > 0 {
+		panic("can't load while there are outstanding update transactions")
+	}
+	return db.loadTable(table, getProgress(progress))
+}
+
+func (db *Database) loadTable(table string, progress Progress) int {
+	f, r, ver := open(table + ".su")
+	defer f.Close()
+	schema := table + " " + readLinePrefixed(r, "====== ")
+	return loadTable(db, r, ver, schema, progress)
+}
+
+// LoadTableFrom imports a single table from a dumped database file.
+// The other tables in the dump are skipped
+// and the other tables in the database are not affected.
+// It returns the number of records loaded or panics on error.
+func LoadTableFrom(from, table, dbfile string, progress ...Progress) int {
+	defer func() {
+		if e := recover(); e != nil {
+			panic("load failed: " + table + " " + fmt.Sprint(e))
+		}
+	}()
+	f, r, ver := open(from)
+	defer f.Close()
+	for {
+		schema := readLinePrefixed(r, "====== ")
+		if schema == "" {
+			panic("can't find " + table + " in " + from)
+		}
+		if str.BeforeFirst(schema, " ") == table {
+			db := openOrCreate(dbfile)
+			defer db.Close()
+			nrecs := loadTable(db, r, ver, schema, getProgress(progress))
+			db.GetState().Write(true)
+			return nrecs
+		}
+		skipRecords(r, ver)
+	}
+}
+
+// openOrCreate opens a database file, creating it if it doesn't exist
+func openOrCreate(dbfile string) *Database {
 	var db *Database
 	var err error
-	if _, err := os.Stat(dbfile); os.IsNotExist(err) {
+	if _, err = os.Stat(dbfile); os.IsNotExist(err) {
 		db, err = CreateDatabase(dbfile)
 	} else {
 		db, err = OpenDatabase(dbfile)
 	}
 	ck(err)
-	defer db.Close()
-	f, r := open(table + ".su")
-	defer f.Close()
-	schema := table + " " + readLinePrefixed(r, "====== ")
-	nrecs := loadTable(db, r, schema)
-	db.GetState().Write(true)
-	return nrecs
+	return db
 }
 
-func open(filename string) (*os.File, *bufio.Reader) {
+// open opens a dump file and reads the header.
+// It returns the file, a reader, and the dump format version.
+func open(filename string) (*os.File, *bufio.Reader, int) {
 	f, err := os.Open(filename)
 	if err != nil {
 		panic(err)
 	}
 	r := bufio.NewReader(f)
-	readLinePrefixed(r, "Suneido dump 2")
-	return f, r
+	ver := readDumpVersion(r)
+	return f, r, ver
+}
+
+// minDumpVersion is the oldest dump file format that can be loaded
+const minDumpVersion = 2
+
+// readDumpVersion reads the dump file header line
+// and returns the format version.
+// It panics if the version is not supported.
+func readDumpVersion(r *bufio.Reader) int {
+	s := strings.TrimSpace(readLinePrefixed(r, "Suneido dump "))
+	ver, err := strconv.Atoi(s)
+	if err != nil {
+		panic("not a valid dump file")
+	}
+	if ver < minDumpVersion || ver > dumpVersion {
+		panic(fmt.Sprintf("unsupported dump version %d (supported %d to %d)",
+			ver, minDumpVersion, dumpVersion))
+	}
+	return ver
 }
 
-func loadTable(db *Database, r *bufio.Reader, schema string) int {
+func loadTable(db *Database, r *bufio.Reader, ver int, schema string,
+	progress Progress) int {
 	trace(schema)
 	rq := compile.ParseRequest("create " + schema)
 
 	store := db.store
 	list := sortlist.NewUnsorted()
 	before := store.Size()
-	nrecs := readRecords(r, store, list)
+	nrecs, cs := readRecords(r, store, list, func(n int) {
+		progress(rq.Schema.Table, n)
+	})
+	if ver >= 3 && readInt(r) != cs {
+		panic("dump corrupted: " + rq.Schema.Table + " checksum mismatch")
+	}
 	beforeIndexes := store.Size()
 	dataSize := beforeIndexes - before
 	trace("nrecs", nrecs, "data size", dataSize)
@@ -102,6 +188,7 @@ func loadTable(db *Database, r *bufio.Reader, schema string) int {
 	trace("indexes size", store.Size()-beforeIndexes)
 	ti := &meta.Info{Table: rq.Schema.Table, Nrows: nrecs, Size: dataSize, Indexes: ov}
 	db.LoadedTable(ts, ti)
+	finalProgress(progress, rq.Schema.Table, nrecs)
 	return nrecs
 }
 
@@ -117,8 +204,13 @@ func readLinePrefixed(r *bufio.Reader, pre string) string {
 	return s[len(pre):]
 }
 
-func readRecords(in *bufio.Reader, store *stor.Stor, list *sortlist.Builder) int {
+// readRecords reads the records for a table into the store.
+// It returns the number of records and their checksum.
+// progress is called every progressInterval records.
+func readRecords(in *bufio.Reader, store *stor.Stor,
+	list *sortlist.Builder, progress func(nrecs int)) (int, uint32) {
 	nrecs := 0
+	cs := uint32(0)
 	intbuf := make([]byte, 4)
 	for { // each record
 		_, err := io.ReadFull(in, intbuf)
@@ -134,10 +226,41 @@ func readRecords(in *bufio.Reader, store *stor.Stor, list *sortlist.Builder) int
 		_, err = io.ReadFull(in, buf[:size])
 		ck(err)
 		cksum.Update(buf)
+		cs = cksum.Extend(cs, buf[:size])
 		list.Add(off)
-		nrecs++
+		if nrecs++; nrecs%progressInterval == 0 {
+			progress(nrecs)
+		}
+	}
+	return nrecs, cs
+}
+
+func readInt(in *bufio.Reader) uint32 {
+	intbuf := make([]byte, 4)
+	_, err := io.ReadFull(in, intbuf)
+	ck(err)
+	return binary.BigEndian.Uint32(intbuf)
+}
+
+// skipRecords reads past the records (and checksum) for a table
+func skipRecords(in *bufio.Reader, ver int) {
+	intbuf := make([]byte, 4)
+	for {
+		_, err := io.ReadFull(in, intbuf)
+		if err == io.EOF {
+			break
+		}
+		ck(err)
+		size := int(binary.BigEndian.Uint32(intbuf))
+		if size == 0 {
+			if ver >= 3 {
+				readInt(in) // checksum
+			}
+			break
+		}
+		_, err = in.Discard(size)
+		ck(err)
 	}
-	return nrecs
 }
 
 func buildIndexes(ts *meta.Schema, list *sortlist.Builder, store *stor.Stor, nrecs int) []*index.Overlay {
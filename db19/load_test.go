@@ -8,6 +8,9 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	rt "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
 )
 
 func TestLoadTable(*testing.T) {
@@ -32,3 +35,60 @@ func TestLoadDatabase(*testing.T) {
 	fmt.Println("loaded", n, "tables in", time.Since(t).Round(time.Millisecond))
 	ck(CheckDatabase("tmp.db"))
 }
+
+func TestLoadTableFrom(t *testing.T) {
+	defer os.Remove("tmp.db")
+	defer os.Remove("tmp.su")
+	defer os.Remove("tmp2.db")
+	os.Remove("tmp2.db")
+	dumpTestDb()
+
+	assert.T(t).This(LoadTableFrom("tmp.su", "bigtable", "tmp2.db")).Is(1)
+	db, err := OpenDatabase("tmp2.db")
+	ck(err)
+	assert.T(t).This(db.GetState().meta.GetRoSchema("mytable")).Is(nil)
+	db.Close()
+
+	assert.T(t).This(LoadTableFrom("tmp.su", "mytable", "tmp2.db")).Is(2)
+	db, err = OpenDatabase("tmp2.db")
+	ck(err)
+	assert.T(t).This(tableData(db, "mytable")).
+		Is([]rt.Record{mkrec("a", "one"), mkrec("b", "two")})
+	assert.T(t).This(tableData(db, "bigtable")).
+		Is([]rt.Record{mkrec("c", "three")})
+	db.Close()
+
+	assert.T(t).This(func() { LoadTableFrom("tmp.su", "nosuch", "tmp2.db") }).
+		Panics("load failed: nosuch can't find nosuch in tmp.su")
+}
+
+func TestDatabaseLoadTable(t *testing.T) {
+	defer os.Remove("tmp.db")
+	defer os.Remove("mytable.su")
+	db := createDb()
+	db.ck = NewCheck()
+	ut := db.NewUpdateTran()
+	ut.Output("mytable", mkrec("a", "one"))
+	ut.Output("mytable", mkrec("b", "two"))
+	commitSync(db, ut)
+	db.Persist(&execPersistSingle{}, true)
+	_, err := db.DumpTable("mytable", "mytable.su")
+	ck(err)
+	ut = db.NewUpdateTran()
+	assert.T(t).This(func() { db.LoadTable("mytable") }).
+		Panics("outstanding update transactions")
+	db.KillTran(ut.num())
+	db.Close()
+
+	db, err = CreateDatabase("tmp.db")
+	ck(err)
+	assert.T(t).This(db.LoadTable("mytable")).Is(2)
+	db.Close()
+	db, err = OpenDatabase("tmp.db")
+	ck(err)
+	defer db.Close()
+	assert.T(t).This(tableData(db, "mytable")).
+		Is([]rt.Record{mkrec("a", "one"), mkrec("b", "two")})
+	assert.T(t).This(func() { db.LoadTable("nosuch") }).
+		Panics("load failed: nosuch open nosuch.su")
+}
@@ -61,6 +61,91 @@ func prevState(store *stor.Stor, off uint64) (off2 uint64, state *DbState, t tim
 	return off2, state, t
 }
 
+// StateTime is the offset and timestamp of a state
+type StateTime struct {
+	Off  uint64
+	Time time.Time
+}
+
+// ListStates returns the offsets and times of the valid states
+// in a database file, from newest to oldest.
+// It does not check the consistency of the states.
+func ListStates(dbfile string) ([]StateTime, error) {
+	store, err := stor.MmapStor(dbfile, stor.READ)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	return listStates(store), nil
+}
+
+func listStates(store *stor.Stor) []StateTime {
+	var list []StateTime
+	off := store.Size()
+	for {
+		off = store.LastOffset(off, magic1)
+		if off == 0 {
+			return list
+		}
+		if t, ok := stateTime(store, off); ok {
+			list = append(list, StateTime{Off: off, Time: t})
+		}
+	}
+}
+
+// StateInfo is the offsets and timestamp of a state
+type StateInfo struct {
+	StateTime
+	OffSchema uint64
+	OffInfo   uint64
+}
+
+// LastState returns the latest valid state in a database file.
+// It only reads the state itself, it does not read the schema or info
+// or check the database, so it is much faster than opening the database.
+func LastState(dbfile string) (StateInfo, error) {
+	store, err := stor.MmapStor(dbfile, stor.READ)
+	if err != nil {
+		return StateInfo{}, err
+	}
+	defer store.Close()
+	return lastState(store)
+}
+
+func lastState(store *stor.Stor) (StateInfo, error) {
+	off := store.Size()
+	for {
+		off = store.LastOffset(off, magic1)
+		if off == 0 {
+			return StateInfo{}, errors.New("no valid states found")
+		}
+		if si, ok := stateInfo(store, off); ok {
+			return si, nil
+		}
+	}
+}
+
+func stateInfo(store *stor.Stor, off uint64) (si StateInfo, ok bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			ok = false
+		}
+	}()
+	offSchema, offInfo, t, _ := readState(store, off)
+	return StateInfo{StateTime: StateTime{Off: off, Time: t},
+		OffSchema: offSchema, OffInfo: offInfo}, true
+}
+
+func stateTime(store *stor.Stor, off uint64) (t time.Time, ok bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			ok = false
+		}
+	}()
+	_, _, t, _ = readState(store, off)
+	return t, true
+}
+
 func checkState(state *DbState, table string) (ec *ErrCorrupt) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -5,7 +5,12 @@ package db19
 
 import (
 	"fmt"
+	"os"
 	"testing"
+	"time"
+
+	"github.com/apmckinlay/gsuneido/db19/stor"
+	"github.com/apmckinlay/gsuneido/util/assert"
 )
 
 func TestRepair(*testing.T) {
@@ -15,3 +20,51 @@ func TestRepair(*testing.T) {
 	err := Repair("../suneido.db", nil)
 	fmt.Println(err)
 }
+
+func TestListStates(t *testing.T) {
+	store := stor.HeapStor(1024)
+	store.Alloc(8) // offset 0 is never a state
+	var offs []uint64
+	for i := 0; i < 3; i++ {
+		_, buf := store.Alloc(20)
+		copy(buf, magic1) // not a valid state
+		offs = append(offs, writeState(store, 1234, 5678))
+	}
+	list := listStates(store)
+	assert.T(t).This(len(list)).Is(3)
+	for i, st := range list {
+		assert.T(t).This(st.Off).Is(offs[len(offs)-1-i])
+		assert.T(t).That(time.Since(st.Time) < time.Minute)
+		if i > 0 {
+			assert.T(t).That(!st.Time.After(list[i-1].Time))
+		}
+	}
+}
+
+func TestLastState(t *testing.T) {
+	store := stor.HeapStor(1024)
+	store.Alloc(8) // offset 0 is never a state
+	_, err := lastState(store)
+	assert.T(t).This(err.Error()).Is("no valid states found")
+	off := writeState(store, 1234, 5678)
+	_, buf := store.Alloc(20)
+	copy(buf, magic1) // not a valid state
+	si, err := lastState(store)
+	assert.T(t).This(err).Is(nil)
+	assert.T(t).This(si.Off).Is(off)
+	assert.T(t).This(si.OffSchema).Is(uint64(1234))
+	assert.T(t).This(si.OffInfo).Is(uint64(5678))
+
+	defer os.Remove("tmp.db")
+	db := createDb()
+	db.Close()
+	si, err = LastState("tmp.db")
+	assert.T(t).This(err).Is(nil)
+	db, err = OpenDatabaseRead("tmp.db")
+	ck(err)
+	defer db.Close()
+	_, tm := ReadState(db.store, si.Off)
+	assert.T(t).This(si.Time).Is(tm)
+	assert.T(t).That(time.Since(si.Time) < time.Minute)
+	assert.T(t).This(si.Off + uint64(stateLen)).Is(db.store.Size())
+}
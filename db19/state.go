@@ -5,6 +5,11 @@ package db19
 
 import (
 	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,6 +17,7 @@ import (
 
 	"github.com/apmckinlay/gsuneido/db19/meta"
 	"github.com/apmckinlay/gsuneido/db19/stor"
+	"github.com/apmckinlay/gsuneido/options"
 	"github.com/apmckinlay/gsuneido/util/assert"
 	"github.com/apmckinlay/gsuneido/util/cksum"
 )
@@ -19,6 +25,8 @@ import (
 type DbState struct {
 	store *stor.Stor
 	meta  *meta.Meta
+	// writer is the optional metadata about who wrote the state, may be nil
+	writer *StateWriter
 }
 
 type stateHolder struct {
@@ -75,6 +83,9 @@ func (db *Database) Merge(fn mergefn, merges *mergeList) {
 		meta.ApplyMerge(updates)
 		state.meta = &meta
 	})
+	if db.ilog != nil {
+		db.ilog.merged(merges.ncommits)
+	}
 }
 
 //-------------------------------------------------------------------
@@ -87,6 +98,8 @@ type persistfn func(*DbState) []meta.PersistUpdate
 func (db *Database) Persist(exec execPersist, flatten bool) uint64 {
 	// fmt.Println("Persist", flatten)
 	var off uint64
+	var ncommits int
+	complete := false
 	db.GetState().meta.Persist(exec.Submit) // outside UpdateState
 	updates := exec.Results()
 	db.UpdateState(func(state *DbState) {
@@ -94,8 +107,17 @@ func (db *Database) Persist(exec execPersist, flatten bool) uint64 {
 		meta := *state.meta // copy
 		meta.ApplyPersist(updates)
 		state.meta = &meta
-		off = state.Write(flatten)
+		off, state.writer = state.Write(flatten)
+		if db.ilog != nil {
+			ncommits, complete = db.ilog.complete()
+		}
 	})
+	db.store.SetCommitted(off + uint64(stateLen))
+	if complete {
+		// If this fails, recovery will just start from an earlier state.
+		// Subsequent commits will fail since the log is unusable.
+		db.ilog.persist(off, ncommits)
+	}
 	return off
 }
 
@@ -106,18 +128,84 @@ const stateLen = len(magic1) + dateSize + 2*stor.SmallOffsetLen +
 	len(magic2) + cksum.Len
 const magic2at = stateLen - len(magic2)
 
-func (state *DbState) Write(flatten bool) uint64 {
+// stateVersion is the current state format version.
+// The version is stored in the high byte of the date
+// so older states (which did not have a version) are version 0.
+// Version 2 states are preceded by StateWriter metadata.
+// readState rejects versions newer than this.
+const stateVersion = 2
+
+// StateWriter is diagnostic metadata about the process that wrote a state.
+// It is only written if options.StateWriter is set.
+type StateWriter struct {
+	Host    string
+	Pid     int
+	Version string
+}
+
+func thisWriter() *StateWriter {
+	host, _ := os.Hostname()
+	return &StateWriter{Host: host, Pid: os.Getpid(),
+		Version: options.BuiltDate}
+}
+
+func (sw *StateWriter) encode() []byte {
+	return []byte(sw.Host + "\x00" + strconv.Itoa(sw.Pid) + "\x00" + sw.Version)
+}
+
+func decodeWriter(buf []byte) *StateWriter {
+	fields := strings.SplitN(string(buf), "\x00", 3)
+	assert.That(len(fields) == 3)
+	pid, err := strconv.Atoi(fields[1])
+	assert.That(err == nil)
+	return &StateWriter{Host: fields[0], Pid: pid, Version: fields[2]}
+}
+
+// Write writes the state to the store and returns its offset
+// and the writer metadata (nil unless options.StateWriter is set).
+// It does not modify the state, since it may be a shared snapshot.
+func (state *DbState) Write(flatten bool) (uint64, *StateWriter) {
 	// NOTE: indexes should already have been saved
 	offSchema, offInfo := state.meta.Write(state.store, flatten)
-	return writeState(state.store, offSchema, offInfo)
+	if options.StateWriter {
+		sw := thisWriter()
+		return writeStateVersion(state.store, stateVersion, sw,
+			offSchema, offInfo), sw
+	}
+	return writeState(state.store, offSchema, offInfo), nil
 }
 
 func writeState(store *stor.Stor, offSchema, offInfo uint64) uint64 {
-	stateOff, buf := store.Alloc(stateLen)
+	return writeStateVersion(store, 1, nil, offSchema, offInfo)
+}
+
+// writeStateVersion writes a state with the given version.
+// For version 2 the writer metadata is written immediately before the state
+// (in the same allocation) followed by its checksum and its two byte length.
+func writeStateVersion(store *stor.Stor, version byte, sw *StateWriter,
+	offSchema, offInfo uint64) uint64 {
+	var md []byte
+	if version >= 2 {
+		md = sw.encode()
+		assert.That(len(md) <= math.MaxUint16)
+	}
+	n := 0
+	if md != nil {
+		n = len(md) + cksum.Len + 2
+	}
+	stateOff, buf := store.Alloc(n + stateLen)
+	if md != nil {
+		copy(buf, md)
+		cksum.Update(buf[:len(md)+cksum.Len])
+		binary.BigEndian.PutUint16(buf[n-2:], uint16(len(md)))
+		stateOff += uint64(n)
+		buf = buf[n:]
+	}
 	copy(buf, magic1)
 	i := len(magic1)
 	t := time.Now().Unix()
 	binary.BigEndian.PutUint64(buf[i:], uint64(t))
+	buf[i] = version
 	i += dateSize
 	stor.WriteSmallOffset(buf[i:], offSchema)
 	i += stor.SmallOffsetLen
@@ -131,22 +219,50 @@ func writeState(store *stor.Stor, offSchema, offInfo uint64) uint64 {
 	return stateOff
 }
 
+// ReadState returns the state at the given offset and the time it was written
 func ReadState(st *stor.Stor, off uint64) (*DbState, time.Time) {
-	offSchema, offInfo, t := readState(st, off)
-	return &DbState{store: st, meta: meta.ReadMeta(st, offSchema, offInfo)}, t
+	offSchema, offInfo, t, sw := readState(st, off)
+	return &DbState{store: st, meta: meta.ReadMeta(st, offSchema, offInfo),
+		writer: sw}, t
 }
 
-func readState(st *stor.Stor, off uint64) (offSchema, offInfo uint64, t time.Time) {
+// Writer returns the metadata about who wrote the state,
+// or nil if the state did not include it
+func (state *DbState) Writer() *StateWriter {
+	return state.writer
+}
+
+func readState(st *stor.Stor, off uint64) (offSchema, offInfo uint64,
+	t time.Time, sw *StateWriter) {
 	buf := st.Data(off)[:stateLen]
 	i := len(magic1)
 	assert.That(string(buf[:i]) == magic1)
 	cksum.MustCheck(buf[:magic2at])
 	assert.That(string(buf[magic2at:magic2at+len(magic2)]) == magic2)
-	t = time.Unix(int64(binary.BigEndian.Uint64(buf[i:])), 0)
+	version := buf[i]
+	if version > stateVersion {
+		panic(fmt.Sprintf("unsupported database state version %d (max %d)",
+			version, stateVersion))
+	}
+	date := binary.BigEndian.Uint64(buf[i:]) &^ (0xff << 56) // remove version
+	t = time.Unix(int64(date), 0)
 	i += dateSize
 	offSchema = stor.ReadSmallOffset(buf[i:])
 	i += stor.SmallOffsetLen
 	offInfo = stor.ReadSmallOffset(buf[i:])
 	i += stor.SmallOffsetLen
-	return offSchema, offInfo, t
+	if version >= 2 {
+		sw = readWriter(st, off)
+	}
+	return offSchema, offInfo, t, sw
+}
+
+// readWriter reads the writer metadata preceding the state at off
+func readWriter(st *stor.Stor, off uint64) *StateWriter {
+	assert.That(off >= 2)
+	n := uint64(binary.BigEndian.Uint16(st.Data(off - 2)))
+	assert.That(off >= n+cksum.Len+2)
+	buf := st.Data(off - 2 - cksum.Len - n)[:n+cksum.Len]
+	cksum.MustCheck(buf)
+	return decodeWriter(buf[:n])
 }
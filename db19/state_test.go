@@ -4,16 +4,78 @@
 package db19
 
 import (
+	"os"
 	"testing"
+	"time"
 
+	"github.com/apmckinlay/gsuneido/db19/meta"
 	"github.com/apmckinlay/gsuneido/db19/stor"
+	"github.com/apmckinlay/gsuneido/options"
 	"github.com/apmckinlay/gsuneido/util/assert"
 )
 
 func TestStateReadWrite(*testing.T) {
 	store := stor.HeapStor(1024)
 	off := writeState(store, 1234, 5678)
-	offSchema, offInfo, _ := readState(store, off)
+	offSchema, offInfo, _, _ := readState(store, off)
 	assert.This(offSchema).Is(1234)
 	assert.This(offInfo).Is(5678)
 }
+
+func TestStateVersion(t *testing.T) {
+	store := stor.HeapStor(1024)
+	for _, v := range []byte{0, 1} {
+		off := writeStateVersion(store, v, nil, 1234, 5678)
+		offSchema, offInfo, date, sw := readState(store, off)
+		assert.T(t).This(offSchema).Is(1234)
+		assert.T(t).This(offInfo).Is(5678)
+		assert.T(t).That(time.Since(date) < time.Minute)
+		assert.T(t).That(sw == nil)
+	}
+	off := writeStateVersion(store, stateVersion+1, &StateWriter{}, 1234, 5678)
+	assert.T(t).This(func() { readState(store, off) }).
+		Panics("unsupported database state version")
+}
+
+func TestStateWriter(t *testing.T) {
+	store := stor.HeapStor(1024)
+	writer := &StateWriter{Host: "myhost", Pid: 123, Version: "Jan 2 2020"}
+	off := writeStateVersion(store, 2, writer, 1234, 5678)
+	offSchema, offInfo, _, sw := readState(store, off)
+	assert.T(t).This(offSchema).Is(1234)
+	assert.T(t).This(offInfo).Is(5678)
+	assert.T(t).This(*sw).Is(*writer)
+
+	// older state following a newer one
+	off = writeState(store, 1234, 5678)
+	_, _, _, sw = readState(store, off)
+	assert.T(t).That(sw == nil)
+
+	// corrupt metadata
+	off = writeStateVersion(store, 2, writer, 1234, 5678)
+	store.Data(off - 5)[0] ^= 1
+	assert.T(t).This(func() { readState(store, off) }).Panics("checksum")
+}
+
+func TestStateWriterOption(t *testing.T) {
+	defer func(b bool) { options.StateWriter = b }(options.StateWriter)
+	options.StateWriter = true
+	state := &DbState{store: stor.HeapStor(1024), meta: &meta.Meta{}}
+	off, writer := state.Write(true)
+	assert.T(t).This(state.Writer()).Is(nil) // Write doesn't modify the state
+	state2, _ := ReadState(state.store, off)
+	sw := state2.Writer()
+	assert.T(t).This(sw.Pid).Is(os.Getpid())
+	assert.T(t).This(*sw).Is(*writer)
+}
+
+func TestPersistWriter(t *testing.T) {
+	defer func(b bool) { options.StateWriter = b }(options.StateWriter)
+	options.StateWriter = true
+	db := createDb()
+	defer func() { db.Close(); os.Remove("tmp.db") }()
+	snapshot := db.GetState()
+	db.Persist(&execPersistSingle{}, true)
+	assert.T(t).This(snapshot.Writer()).Is(nil)
+	assert.T(t).This(db.GetState().Writer().Pid).Is(os.Getpid())
+}
@@ -21,9 +21,40 @@ func HeapStor(chunksize int) *Stor {
 	return hs
 }
 
+// HeapStorLimited returns an empty in-memory stor
+// that panics with "temp storage exhausted"
+// if an allocation would make it larger than limit bytes.
+func HeapStorLimited(chunksize int, limit uint64) *Stor {
+	hs := HeapStor(chunksize)
+	hs.limit = limit
+	return hs
+}
+
 func (hs heapStor) Get(int) []byte {
 	return make([]byte, hs.chunksize)
 }
 
 func (hs heapStor) Close(int64) {
 }
+
+// Clone returns an independent in-memory copy of a stor
+// containing its data up to Size. It is intended for testing.
+func (s *Stor) Clone() *Stor {
+	size := s.Size()
+	chunks := s.chunks.Load().([][]byte)
+	hs := NewStor(&heapStor{int(s.chunksize)}, s.chunksize, size)
+	hs.threshold = s.threshold
+	clone := make([][]byte, len(chunks))
+	for i, c := range chunks {
+		clone[i] = make([]byte, s.chunksize)
+		if off := s.chunkToOffset(i); off < size {
+			n := size - off
+			if n > s.chunksize {
+				n = s.chunksize
+			}
+			copy(clone[i], c[:n])
+		}
+	}
+	hs.chunks.Store(clone)
+	return hs
+}
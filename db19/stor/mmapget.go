@@ -9,7 +9,7 @@ import (
 	"syscall"
 )
 
-// NOTE: no provision for unmapping (same as Java)
+// NOTE: chunks are only unmapped by Truncate
 
 // Get returns a memory mapped portion of a file.
 // It panics on error.
@@ -28,6 +28,13 @@ func (ms *mmapStor) Get(chunk int) []byte {
 	return mmap
 }
 
+// Unmap releases a chunk returned by Get. It panics on error.
+func (ms *mmapStor) Unmap(chunk []byte) {
+	if err := syscall.Munmap(chunk); err != nil {
+		panic(err)
+	}
+}
+
 func (ms *mmapStor) Close(size int64) {
 	ms.file.Truncate(size)
 	ms.file.Close()
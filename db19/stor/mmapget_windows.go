@@ -50,6 +50,18 @@ func (ms *mmapStor) Get(chunk int) []byte {
 	return (*[MMAP_CHUNKSIZE]byte)(unsafe.Pointer(ptr))[:]
 }
 
+// Unmap releases a chunk returned by Get
+func (ms *mmapStor) Unmap(chunk []byte) {
+	ptr := uintptr(unsafe.Pointer(&chunk[0]))
+	for i, p := range ms.ptrs {
+		if p == ptr {
+			syscall.UnmapViewOfFile(ptr)
+			ms.ptrs = append(ms.ptrs[:i], ms.ptrs[i+1:]...)
+			return
+		}
+	}
+}
+
 func (ms mmapStor) Close(size int64) {
 	// MSDN: Although an application may close the file handle used to create
 	// a file mapping object, the system holds the corresponding file open
@@ -70,6 +70,7 @@ func MmapStor(filename string, mode Mode) (*Stor, error) {
 	}
 
 	ms := NewStor(impl, MMAP_CHUNKSIZE, uint64(size))
+	ms.committed = uint64(size) // existing data can't be truncated
 	ms.chunks.Store(chunks)
 	return ms, nil
 }
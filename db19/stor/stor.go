@@ -31,6 +31,11 @@ type storage interface {
 	Close(size int64)
 }
 
+// unmapper is implemented by storage that can release a chunk
+type unmapper interface {
+	Unmap(chunk []byte)
+}
+
 // Stor is the externally visible storage
 type Stor struct {
 	impl storage
@@ -43,20 +48,47 @@ type Stor struct {
 	// size is the currently used amount.
 	// It must be accessed in a thread safe way.
 	size uint64
+	// limit is the maximum size, zero means no limit
+	limit uint64
 	// chunks must be initialized up to size,
 	// with at least one chunk if size is 0
 	chunks atomic.Value // [][]byte
 	lock   sync.Mutex
+	// committed is the size that Truncate will not go below.
+	// It is guarded by lock.
+	committed uint64
+	// truncating is set (atomically) while Truncate is running.
+	// It is only checked by the Alloc slow path, to keep the fast path fast.
+	truncating int32
 }
 
 func NewStor(impl storage, chunksize uint64, size uint64) *Stor {
 	shift := bits.TrailingZeros(uint(chunksize))
 	assert.That(1<<shift == chunksize) // chunksize must be power of 2
-	threshold := chunksize * 3 / 4     // ???
+	threshold := chunksize * 3 / 4     // see SetPrefetch
 	return &Stor{impl: impl, chunksize: chunksize, threshold: threshold,
 		shift: shift, size: size}
 }
 
+// SetPrefetch sets the fraction of a chunk at which Alloc
+// proactively gets the next chunk. The default is 3/4.
+// A lower value may help write heavy bulk loads.
+// It must be between 0 and 1 (exclusive)
+// and must be set before the stor is used concurrently.
+func (s *Stor) SetPrefetch(fraction float64) {
+	threshold := uint64(fraction * float64(s.chunksize))
+	if fraction <= 0 || fraction >= 1 || threshold >= s.chunksize {
+		panic("stor prefetch must be between 0 and 1")
+	}
+	s.threshold = threshold
+}
+
+// Prefetch returns the fraction of a chunk at which Alloc
+// proactively gets the next chunk.
+func (s *Stor) Prefetch() float64 {
+	return float64(s.threshold) / float64(s.chunksize)
+}
+
 // Alloc allocates n bytes of storage and returns its Offset and byte slice
 // Returning data here allows slicing to the correct length and capacity
 // to prevent erroneously writing too far.
@@ -71,6 +103,9 @@ func (s *Stor) Alloc(n int) (Offset, []byte) {
 		chunk := s.offsetToChunk(newsize)
 		nchunks := s.offsetToChunk(oldsize + s.chunksize - 1)
 		if chunk >= nchunks { // straddle
+			if atomic.LoadInt32(&s.truncating) != 0 {
+				panic("stor: Alloc during Truncate")
+			}
 			chunks := s.chunks.Load().([][]byte)
 			if chunk >= len(chunks) {
 				s.getChunk(chunk)
@@ -78,6 +113,9 @@ func (s *Stor) Alloc(n int) (Offset, []byte) {
 			offset = s.chunkToOffset(chunk)
 			newsize = offset + uint64(n)
 		}
+		if s.limit != 0 && newsize > s.limit {
+			panic("temp storage exhausted")
+		}
 		// attempt to confirm our allocation
 		if atomic.CompareAndSwapUint64(&s.size, oldsize, newsize) {
 			// proactively get next chunk if we passed the threshold
@@ -94,7 +132,7 @@ func (s *Stor) Alloc(n int) (Offset, []byte) {
 func (s *Stor) getChunk(chunk int) {
 	s.lock.Lock() // note: lock does not prevent concurrent allocations
 	chunks := s.chunks.Load().([][]byte)
-	if chunk >= len(chunks) {
+	if chunk == len(chunks) {
 		// no one else beat us to it
 		chunks = append(chunks, s.impl.Get(chunk))
 		s.chunks.Store(chunks)
@@ -114,6 +152,21 @@ func (s *Stor) Data(offset Offset) []byte {
 	return c[offset&(s.chunksize-1):]
 }
 
+// ReadCopy returns an independent copy of n bytes starting at offset.
+// Data is cheaper but returns a slice into the (possibly mapped) storage
+// so it must not be retained after the stor is closed.
+// ReadCopy should be used when the bytes must outlive the stor.
+// Like allocations, the range may not straddle chunks.
+func (s *Stor) ReadCopy(offset Offset, n int) []byte {
+	data := s.Data(offset)
+	if n > len(data) {
+		panic("stor: ReadCopy past end of chunk")
+	}
+	buf := make([]byte, n)
+	copy(buf, data)
+	return buf
+}
+
 func (s *Stor) offsetToChunk(offset Offset) int {
 	return int(offset >> s.shift)
 }
@@ -128,6 +181,25 @@ func (s *Stor) Size() uint64 {
 	return atomic.LoadUint64(&s.size)
 }
 
+// Stats are statistics about a Stor
+type Stats struct {
+	// Chunks is the number of chunks currently mapped
+	Chunks int
+	// ChunkSize is the size of each chunk
+	ChunkSize uint64
+	// Size is the current (allocated) size of the data
+	Size uint64
+	// Mapped is the total size of the mapped chunks
+	Mapped uint64
+}
+
+// Stats returns the current statistics for the stor
+func (s *Stor) Stats() Stats {
+	chunks := s.chunks.Load().([][]byte)
+	return Stats{Chunks: len(chunks), ChunkSize: s.chunksize, Size: s.Size(),
+		Mapped: uint64(len(chunks)) * s.chunksize}
+}
+
 // LastOffset searches backwards from a given offset for a given byte slice
 // and returns the offset, or 0 if not found
 func (s *Stor) LastOffset(off uint64, str string) uint64 {
@@ -145,6 +217,82 @@ func (s *Stor) LastOffset(off uint64, str string) uint64 {
 	return 0
 }
 
+// SetCommitted records that the data up to size has been committed
+// (e.g. by writing a database state) so Truncate will not discard it.
+// The committed size only increases.
+func (s *Stor) SetCommitted(size uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if size > s.committed {
+		s.committed = size
+	}
+}
+
+// Truncate reduces the size of the stor to the given size,
+// discarding any data after it.
+// WARNING: This is destructive. Any data past size,
+// including buffers from allocations that completed before the truncate,
+// must no longer be referenced.
+// Memory mapped chunks past the new size are unmapped
+// so slices from Data must not be retained (see ReadCopy).
+// It panics if size is larger than the current size,
+// less than the committed size (see SetCommitted),
+// or if the stor is read-only.
+//
+// Truncate requires exclusive use of the stor,
+// there must not be any concurrent Alloc.
+// To keep Alloc lock free this is only partially enforced.
+// File storage is shrunk to the new size by Close.
+func (s *Stor) Truncate(size uint64) {
+	if ms, ok := s.impl.(*mmapStor); ok && ms.mode == READ {
+		panic("stor: can't truncate read-only storage")
+	}
+	if !atomic.CompareAndSwapInt32(&s.truncating, 0, 1) {
+		panic("stor: concurrent Truncate")
+	}
+	defer atomic.StoreInt32(&s.truncating, 0)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	oldsize := atomic.LoadUint64(&s.size)
+	if size > oldsize {
+		panic("stor: can't truncate to larger than current size")
+	}
+	if size < s.committed {
+		panic("stor: can't truncate below committed data")
+	}
+	atomic.StoreUint64(&s.size, size)
+	nchunks := s.offsetToChunk(size + s.chunksize - 1)
+	if nchunks < 1 {
+		nchunks = 1
+	}
+	last := nchunks - 1
+	chunks := s.chunks.Load().([][]byte)
+	if nchunks < len(chunks) {
+		if um, ok := s.impl.(unmapper); ok {
+			for _, c := range chunks[nchunks:] {
+				um.Unmap(c)
+			}
+		}
+		// copy so later appends don't overwrite the old slice
+		chunks = append([][]byte(nil), chunks[:nchunks]...)
+	}
+	// Zero the discarded data in the (new) last chunk.
+	// This is writable because the chunks that MmapStor maps read-only
+	// are all before the committed size.
+	base := s.chunkToOffset(last)
+	if oldsize > size && size-base < s.chunksize {
+		end := oldsize - base
+		if end > s.chunksize {
+			end = s.chunksize
+		}
+		buf := chunks[last][size-base : end]
+		for i := range buf {
+			buf[i] = 0
+		}
+	}
+	s.chunks.Store(chunks)
+}
+
 type writable interface {
 	Write(off uint64, data []byte)
 }
@@ -6,6 +6,7 @@ package stor
 import (
 	"math/rand"
 	"os"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -37,6 +38,20 @@ func TestData(t *testing.T) {
 	}
 }
 
+func TestReadCopy(t *testing.T) {
+	hs := HeapStor(64)
+	hs.Alloc(12)
+	offset, buf := hs.Alloc(12)
+	copy(buf, "hello world!")
+	data := hs.ReadCopy(offset, 5)
+	assert.T(t).This(string(data)).Is("hello")
+	hs.Write(offset, []byte("HELLO"))
+	hs.Close()
+	assert.T(t).This(string(data)).Is("hello") // unaffected by write or close
+	assert.T(t).This(func() { hs.ReadCopy(offset, 53) }).
+		Panics("past end of chunk")
+}
+
 func TestMmapRead(t *testing.T) {
 	ms, _ := MmapStor("stor_test.go", READ) // use code as test file
 	buf := ms.Data(0)
@@ -121,3 +136,188 @@ func TestStress(*testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestStats(t *testing.T) {
+	assert := assert.T(t).This
+	hs := HeapStor(64)
+	st := hs.Stats()
+	assert(st).Is(Stats{Chunks: 1, ChunkSize: 64, Size: 0, Mapped: 64})
+	hs.Alloc(40)
+	assert(hs.Stats().Chunks).Is(1)
+	hs.Alloc(20) // passes threshold so proactively gets next chunk
+	assert(hs.Stats().Chunks).Is(2)
+	hs.Alloc(40) // straddles into second chunk
+	st = hs.Stats()
+	assert(st.Chunks).Is(2)
+	assert(st.Size).Is(uint64(104))
+	hs.Alloc(40) // straddles into third chunk
+	st = hs.Stats()
+	assert(st.Chunks).Is(3)
+	assert(st.Size).Is(uint64(168))
+	assert(st.Mapped).Is(uint64(3 * 64))
+}
+
+func TestSetPrefetch(t *testing.T) {
+	assert := assert.T(t).This
+	hs := HeapStor(64)
+	assert(hs.Prefetch()).Is(.75)
+	hs.SetPrefetch(.25)
+	assert(hs.Prefetch()).Is(.25)
+	hs.Alloc(20)
+	assert(hs.Stats().Chunks).Is(2) // passed 16
+	assert(func() { hs.SetPrefetch(0) }).Panics("between 0 and 1")
+	assert(func() { hs.SetPrefetch(1) }).Panics("between 0 and 1")
+	assert(func() { hs.SetPrefetch(1.5) }).Panics("between 0 and 1")
+}
+
+func TestHeapStorLimited(t *testing.T) {
+	hs := HeapStorLimited(64, 100)
+	hs.Alloc(60)
+	hs.Alloc(36) // second chunk, up to the limit
+	assert.T(t).This(hs.Size()).Is(uint64(100))
+	assert.T(t).This(func() { hs.Alloc(1) }).Panics("temp storage exhausted")
+	assert.T(t).This(hs.Size()).Is(uint64(100))
+
+	hs = HeapStorLimited(64, 100)
+	hs.Alloc(60)
+	// straddling would start at 64 so 50 would go past the limit
+	assert.T(t).This(func() { hs.Alloc(50) }).Panics("temp storage exhausted")
+}
+
+func TestClone(t *testing.T) {
+	assert := assert.T(t).This
+	hs := HeapStor(64)
+	off1, buf := hs.Alloc(40)
+	copy(buf, "hello world")
+	off2, buf := hs.Alloc(40) // second chunk
+	copy(buf, "goodbye")
+	c := hs.Clone()
+	assert(c.Size()).Is(hs.Size())
+	assert(c.Stats().Chunks).Is(hs.Stats().Chunks)
+	assert(string(c.Data(off1)[:11])).Is("hello world")
+	assert(string(c.Data(off2)[:7])).Is("goodbye")
+	copy(c.Data(off1), "HELLO")
+	copy(c.Data(off2), "GOOD")
+	c.Alloc(40)
+	assert(string(hs.Data(off1)[:11])).Is("hello world")
+	assert(string(hs.Data(off2)[:7])).Is("goodbye")
+	assert(hs.Size()).Is(off2 + 40)
+}
+
+func TestTruncate(t *testing.T) {
+	assert := assert.T(t).This
+	hs := HeapStor(64)
+	off1, buf := hs.Alloc(40)
+	copy(buf, "hello world")
+	off2, buf := hs.Alloc(40) // second chunk
+	copy(buf, "goodbye")
+	hs.Alloc(40) // third chunk
+	assert(hs.Stats().Chunks).Is(3)
+	assert(func() { hs.Truncate(hs.Size() + 1) }).Panics("larger than")
+
+	hs.Truncate(off2 + 4)
+	assert(hs.Size()).Is(off2 + 4)
+	assert(hs.Stats().Chunks).Is(2)
+	assert(string(hs.Data(off1)[:11])).Is("hello world")
+	assert(string(hs.Data(off2)[:7])).Is("good\x00\x00\x00")
+	off, _ := hs.Alloc(8)
+	assert(off).Is(off2 + 4)
+	off, _ = hs.Alloc(60) // straddles into new third chunk
+	assert(off).Is(Offset(128))
+
+	hs.Truncate(0)
+	assert(hs.Size()).Is(uint64(0))
+	assert(hs.Stats().Chunks).Is(1)
+	assert(hs.Data(off1)[0]).Is(byte(0))
+	off, _ = hs.Alloc(12)
+	assert(off).Is(Offset(0))
+
+	hs.Alloc(40)
+	hs.SetCommitted(20)
+	hs.SetCommitted(10) // committed only increases
+	assert(func() { hs.Truncate(19) }).Panics("below committed")
+	hs.Truncate(20)
+	assert(hs.Size()).Is(uint64(20))
+
+	hs.getChunk(3) // stale, must not be appended in the wrong slot
+	assert(hs.Stats().Chunks).Is(1)
+
+	ms, _ := MmapStor("stor_test.go", READ)
+	defer ms.Close()
+	assert(func() { ms.Truncate(0) }).Panics("read-only")
+
+	defer os.Remove("stor_test.tmp")
+	ms, _ = MmapStor("stor_test.tmp", CREATE)
+	_, buf = ms.Alloc(100)
+	for i := range buf {
+		buf[i] = byte(i + 1)
+	}
+	ms.Truncate(50)
+	ms.Close()
+	ms, _ = MmapStor("stor_test.tmp", UPDATE)
+	assert(ms.Size()).Is(uint64(50))
+	assert(ms.Data(0)[49]).Is(byte(50))
+	ms.Alloc(10)
+	// existing data is committed
+	assert(func() { ms.Truncate(49) }).Panics("below committed")
+	ms.Alloc(MMAP_CHUNKSIZE) // second chunk (and prefetches third)
+	assert(ms.Stats().Chunks).Is(3)
+	ms.Truncate(MMAP_CHUNKSIZE) // unmaps the second and third chunks
+	assert(ms.Stats().Chunks).Is(1)
+	off, _ = ms.Alloc(10)
+	assert(off).Is(Offset(MMAP_CHUNKSIZE))
+	assert(ms.Stats().Chunks).Is(2)
+	ms.Close()
+	fi, err := os.Stat("stor_test.tmp")
+	assert(err).Is(nil)
+	assert(fi.Size()).Is(int64(MMAP_CHUNKSIZE + 10)) // Close shrinks the file
+}
+
+type unmapStor struct {
+	heapStor
+	unmapped int
+}
+
+func (us *unmapStor) Unmap([]byte) {
+	us.unmapped++
+}
+
+func TestTruncateUnmap(t *testing.T) {
+	us := &unmapStor{heapStor: heapStor{64}}
+	s := NewStor(us, 64, 0)
+	s.chunks.Store([][]byte{make([]byte, 64)})
+	for i := 0; i < 4; i++ {
+		s.Alloc(40)
+	}
+	assert.T(t).This(s.Stats().Chunks).Is(4)
+	s.Truncate(40)
+	assert.T(t).This(us.unmapped).Is(3)
+	assert.T(t).This(s.Stats().Chunks).Is(1)
+}
+
+func TestTruncateExclusive(t *testing.T) {
+	hs := HeapStor(64)
+	hs.Alloc(40)
+	hs.truncating = 1 // simulate a Truncate in progress
+	assert.T(t).This(func() { hs.Truncate(0) }).Panics("concurrent Truncate")
+	assert.T(t).This(func() { hs.Alloc(40) }).Panics("Alloc during Truncate")
+	hs.truncating = 0
+	hs.Truncate(0)
+	off, _ := hs.Alloc(40)
+	assert.T(t).This(off).Is(Offset(0))
+}
+
+func BenchmarkPrefetch(b *testing.B) {
+	for _, frac := range []float64{.25, .5, .75, .95} {
+		b.Run(strconv.FormatFloat(frac, 'f', -1, 64), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				hs := HeapStor(64 * 1024)
+				hs.SetPrefetch(frac)
+				for j := 0; j < 10000; j++ {
+					_, buf := hs.Alloc(100)
+					buf[0] = byte(j)
+				}
+			}
+		})
+	}
+}
@@ -4,7 +4,11 @@
 package db19
 
 import (
+	"fmt"
+
 	"github.com/apmckinlay/gsuneido/db19/meta"
+	"github.com/apmckinlay/gsuneido/db19/stor"
+	"github.com/apmckinlay/gsuneido/options"
 	rt "github.com/apmckinlay/gsuneido/runtime"
 	"github.com/apmckinlay/gsuneido/util/cksum"
 )
@@ -23,12 +27,36 @@ func (db *Database) NewReadTran() *ReadTran {
 	return &ReadTran{tran: tran{db: db, meta: state.meta}}
 }
 
+// Transactions returns the outstanding update transactions.
+// Read transactions are not tracked.
+func (db *Database) Transactions() []TranInfo {
+	if db.ck == nil {
+		return nil
+	}
+	return db.ck.Transactions()
+}
+
+// KillTran aborts the update transaction with the given number.
+// Subsequent operations by the transaction will fail.
+// It returns false if the transaction was not found.
+func (db *Database) KillTran(tn int) bool {
+	if db.ck == nil {
+		return false
+	}
+	return db.ck.Kill(tn)
+}
+
 type UpdateTran struct {
 	tran
 	ct *CkTran
+	// outputs is only used if there is an intent log
+	outputs []logOutput
 }
 
 func (db *Database) NewUpdateTran() *UpdateTran {
+	if db.mode == stor.READ {
+		panic("can't update a read-only database")
+	}
 	state := db.GetState()
 	meta := state.meta.Mutable()
 	ct := db.ck.StartTran()
@@ -57,9 +85,16 @@ func (t *UpdateTran) Output(table string, rec rt.Record) {
 	ts := t.getSchema(table)
 	ti := t.getInfo(table)
 	n := rec.Len()
+	if n > options.MaxRecordSize {
+		panic(fmt.Sprintf("output %s: record too large (%d bytes, limit %d)",
+			table, n, options.MaxRecordSize))
+	}
 	off, buf := t.db.store.Alloc(n + cksum.Len)
 	copy(buf, rec[:n])
 	cksum.Update(buf)
+	if t.db.ilog != nil {
+		t.outputs = append(t.outputs, logOutput{table: table, off: off, n: n})
+	}
 	keys := make([]string, len(ts.Indexes))
 	for i := range ts.Indexes {
 		is := ts.Indexes[i].Ixspec
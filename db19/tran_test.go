@@ -15,6 +15,7 @@ import (
 	"github.com/apmckinlay/gsuneido/db19/index/ixspec"
 	"github.com/apmckinlay/gsuneido/db19/meta"
 	"github.com/apmckinlay/gsuneido/db19/meta/schema"
+	"github.com/apmckinlay/gsuneido/options"
 	rt "github.com/apmckinlay/gsuneido/runtime"
 	"github.com/apmckinlay/gsuneido/util/assert"
 )
@@ -104,7 +105,7 @@ func createDb() *Database {
 	ts := &meta.Schema{Schema: schema.Schema{
 		Table:   "mytable",
 		Columns: []string{"one", "two"},
-		Indexes: []schema.Index{{Columns: []string{"one"}, Ixspec: is}},
+		Indexes: []schema.Index{{Columns: []string{"one"}, Mode: 'k', Ixspec: is}},
 	}}
 	ov := index.NewOverlay(db.store, &is)
 	ov.Save()
@@ -134,3 +135,54 @@ func mkrec(args ...string) rt.Record {
 	}
 	return b.Build()
 }
+
+func TestMaxRecordSize(t *testing.T) {
+	defer func(n int) { options.MaxRecordSize = n }(options.MaxRecordSize)
+	db := createDb()
+	defer os.Remove("tmp.db")
+	defer db.Close()
+	db.ck = NewCheck()
+	rec := mkrec("hello", "world")
+	ut := db.NewUpdateTran()
+	options.MaxRecordSize = rec.Len()
+	ut.Output("mytable", rec) // at the limit is ok
+	options.MaxRecordSize = rec.Len() - 1
+	assert.T(t).This(func() { ut.Output("mytable", rec) }).
+		Panics("output mytable: record too large")
+}
+
+func TestReadOnlyUpdate(t *testing.T) {
+	db := createDb()
+	defer os.Remove("tmp.db")
+	db.ck = NewCheck()
+	ut := db.NewUpdateTran()
+	ut.Output("mytable", mkrec("a", "one"))
+	commitSync(db, ut)
+	db.Persist(&execPersistSingle{}, true)
+	db.Close()
+
+	db, err := OpenDatabaseRead("tmp.db")
+	ck(err)
+	defer db.Close()
+	assert.T(t).This(tableRecs(db, "mytable")).Is(1)
+	assert.T(t).This(func() { db.NewUpdateTran() }).
+		Panics("can't update a read-only database")
+}
+
+func TestKillTran(t *testing.T) {
+	defer os.Remove("tmp.db")
+	db := createDb()
+	db.ck = NewCheck()
+	defer db.Close()
+	assert.T(t).This(db.KillTran(1)).Is(false)
+	ut := db.NewUpdateTran()
+	ut.Output("mytable", mkrec("a", "one"))
+	list := db.Transactions()
+	assert.T(t).This(len(list)).Is(1)
+	assert.T(t).This(db.KillTran(list[0].Num)).Is(true)
+	assert.T(t).This(len(db.Transactions())).Is(0)
+	assert.T(t).This(func() { ut.Output("mytable", mkrec("b", "two")) }).
+		Panics("transaction aborted: killed")
+	assert.T(t).This(func() { ut.Commit() }).Panics("transaction")
+	assert.T(t).This(db.KillTran(list[0].Num)).Is(false)
+}
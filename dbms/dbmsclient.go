@@ -236,7 +236,7 @@ func (dc *dbmsClient) Token() string {
 func (dc *dbmsClient) Transaction(update bool) ITran {
 	dc.PutCmd(commands.Transaction).PutBool(update).Request()
 	tn := dc.GetInt()
-	return &TranClient{dc: dc, tn: tn}
+	return &TranClient{dc: dc, tn: tn, update: update}
 }
 
 func (dc *dbmsClient) Transactions() *SuObject {
@@ -286,8 +286,9 @@ func (dc *dbmsClient) getRow(adr int) Row {
 // ------------------------------------------------------------------
 
 type TranClient struct {
-	dc *dbmsClient
-	tn int
+	dc     *dbmsClient
+	tn     int
+	update bool
 }
 
 var _ ITran = (*TranClient)(nil)
@@ -305,6 +306,7 @@ func (tc *TranClient) Complete() string {
 }
 
 func (tc *TranClient) Erase(adr int) {
+	tc.ckUpdate("Erase")
 	tc.dc.PutCmd(commands.Erase).PutInt(tc.tn).PutInt(adr).Request()
 }
 
@@ -324,11 +326,13 @@ func (tc *TranClient) ReadCount() int {
 }
 
 func (tc *TranClient) Request(request string) int {
+	tc.ckUpdate("Request")
 	tc.dc.PutCmd(commands.Request).PutInt(tc.tn).PutStr(request).Request()
 	return tc.dc.GetInt()
 }
 
 func (tc *TranClient) Update(adr int, rec Record) int {
+	tc.ckUpdate("Update")
 	tc.dc.PutCmd(commands.Update).
 		PutInt(tc.tn).PutInt(adr).PutStr(string(rec)).Request()
 	return tc.dc.GetInt()
@@ -339,6 +343,14 @@ func (tc *TranClient) WriteCount() int {
 	return tc.dc.GetInt()
 }
 
+// ckUpdate fails immediately (without a round trip to the server)
+// if a write is attempted in a read-only transaction
+func (tc *TranClient) ckUpdate(op string) {
+	if !tc.update {
+		panic(op + ": can't write in a read-only transaction")
+	}
+}
+
 func (tc *TranClient) String() string {
 	return "Transaction" + strconv.Itoa(tc.tn)
 }
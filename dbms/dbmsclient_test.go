@@ -0,0 +1,21 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package dbms
+
+import (
+	"testing"
+
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestReadTranWrite(t *testing.T) {
+	assert := assert.T(t).This
+	tc := &TranClient{tn: 1} // read-only, no connection needed
+	assert(func() { tc.Request("insert { a: 1 } into tbl") }).
+		Panics("Request: can't write in a read-only transaction")
+	assert(func() { tc.Erase(123) }).
+		Panics("Erase: can't write in a read-only transaction")
+	assert(func() { tc.Update(123, "") }).
+		Panics("Update: can't write in a read-only transaction")
+}
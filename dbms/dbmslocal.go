@@ -10,8 +10,10 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apmckinlay/gsuneido/db19"
+	"github.com/apmckinlay/gsuneido/db19/meta/schema"
 	. "github.com/apmckinlay/gsuneido/runtime"
 	"github.com/apmckinlay/gsuneido/util/str"
 )
@@ -30,6 +32,7 @@ func NewDbmsLocal(db *db19.Database) IDbms {
 // Dbms interface
 
 var _ IDbms = (*DbmsLocal)(nil)
+var _ ISchema = (*DbmsLocal)(nil)
 
 func (DbmsLocal) Admin(string) {
 	panic("DbmsLocal Admin not implemented")
@@ -46,8 +49,15 @@ func (dbms DbmsLocal) Check() string {
 	return ""
 }
 
+// Connections returns a list with the single local "connection"
+// (the session id, or "local" if it has not been set)
+// so code shared with client-server can work standalone.
 func (DbmsLocal) Connections() Value {
-	return EmptyObject
+	id := sessionId
+	if id == "" {
+		id = "local"
+	}
+	return NewSuObject(SuStr(id))
 }
 
 func (DbmsLocal) Cursor(string) ICursor {
@@ -90,16 +100,44 @@ func (DbmsLocal) Get(int, string, Dir) (Row, *Header) {
 	panic("DbmsLocal Get not implemented")
 }
 
-func (DbmsLocal) Info() Value {
-	panic("DbmsLocal Info not implemented")
+// startTime is used by Info for uptime
+var startTime = time.Now()
+
+func (dbms DbmsLocal) Info() Value {
+	ob := &SuObject{}
+	ob.Set(SuStr("uptime"), IntVal(int(time.Since(startTime).Seconds())))
+	ob.Set(SuStr("connections"), One)
+	ob.Set(SuStr("transactions"), IntVal(len(dbms.db.Transactions())))
+	st := dbms.db.StorStats()
+	ob.Set(SuStr("currentSize"), Int64Val(int64(st.Size)))
+	ob.Set(SuStr("chunks"), IntVal(st.Chunks))
+	ob.Set(SuStr("chunkSize"), Int64Val(int64(st.ChunkSize)))
+	ob.Set(SuStr("mapped"), Int64Val(int64(st.Mapped)))
+	if sw := dbms.db.GetState().Writer(); sw != nil {
+		ob.Set(SuStr("stateHost"), SuStr(sw.Host))
+		ob.Set(SuStr("statePid"), IntVal(sw.Pid))
+		ob.Set(SuStr("stateVersion"), SuStr(sw.Version))
+	}
+	return ob
 }
 
-func (DbmsLocal) Kill(string) int {
-	panic("DbmsLocal Kill not implemented")
+// Kill aborts a transaction.
+// Standalone there are no other sessions,
+// so the argument is a transaction number (as from Transactions).
+// It returns the number of transactions aborted (0 or 1).
+func (dbms DbmsLocal) Kill(tran string) int {
+	tn, err := strconv.Atoi(tran)
+	if err != nil {
+		panic("Kill: invalid transaction number: " + tran)
+	}
+	if dbms.db.KillTran(tn) {
+		return 1
+	}
+	return 0
 }
 
-func (DbmsLocal) Load(string) int {
-	panic("DbmsLocal Load not implemented")
+func (dbms DbmsLocal) Load(table string) int {
+	return dbms.db.LoadTable(table)
 }
 
 func (DbmsLocal) LibGet(name string) (result []string) {
@@ -140,6 +178,64 @@ func (DbmsLocal) Run(string) Value {
 	panic("DbmsLocal Run not implemented")
 }
 
+func (dbms DbmsLocal) Schema(table string) Value {
+	sc := dbms.db.Schema(table)
+	if sc == nil {
+		return False
+	}
+	return schemaOb(sc)
+}
+
+// schemaOb returns an object describing a schema e.g.
+// #(table: "tbl", columns: #(a, b), derived: #(), keys: #(#(a)),
+// indexes: #(#(columns: #(a), mode: "key"), #(columns: #(b), mode: "index")))
+// mode is "key", "index", or "unique".
+// Indexes with foreign keys also have fktable and fkcolumns.
+func schemaOb(sc *schema.Schema) *SuObject {
+	ob := &SuObject{}
+	ob.Set(SuStr("table"), SuStr(sc.Table))
+	ob.Set(SuStr("columns"), strsOb(sc.Columns))
+	ob.Set(SuStr("derived"), strsOb(sc.Derived))
+	keys := &SuObject{}
+	indexes := &SuObject{}
+	for i := range sc.Indexes {
+		ix := &sc.Indexes[i]
+		if ix.Mode == 'k' {
+			keys.Add(strsOb(ix.Columns))
+		}
+		iob := &SuObject{}
+		iob.Set(SuStr("columns"), strsOb(ix.Columns))
+		iob.Set(SuStr("mode"), SuStr(indexMode(ix.Mode)))
+		if ix.Fktable != "" {
+			iob.Set(SuStr("fktable"), SuStr(ix.Fktable))
+			iob.Set(SuStr("fkcolumns"), strsOb(ix.Fkcolumns))
+		}
+		indexes.Add(iob)
+	}
+	ob.Set(SuStr("keys"), keys)
+	ob.Set(SuStr("indexes"), indexes)
+	return ob
+}
+
+func indexMode(mode int) string {
+	switch mode {
+	case 'k':
+		return "key"
+	case 'u':
+		return "unique"
+	default:
+		return "index"
+	}
+}
+
+func strsOb(list []string) *SuObject {
+	ob := &SuObject{}
+	for _, s := range list {
+		ob.Add(SuStr(s))
+	}
+	return ob
+}
+
 var sessionId string
 
 func (DbmsLocal) SessionId(id string) string {
@@ -149,8 +245,8 @@ func (DbmsLocal) SessionId(id string) string {
 	return sessionId
 }
 
-func (DbmsLocal) Size() int64 {
-	panic("DbmsLocal Size not implemented")
+func (dbms DbmsLocal) Size() int64 {
+	return int64(dbms.db.StorStats().Size)
 }
 
 func (DbmsLocal) Token() string {
@@ -172,8 +268,21 @@ func (DbmsLocal) Timestamp() SuDate {
 	return t
 }
 
-func (DbmsLocal) Transactions() *SuObject {
-	panic("DbmsLocal Transactions not implemented")
+// Transactions returns a list of the outstanding update transactions
+// e.g. #(#(tran: 12, type: "update", start: #20210101.1200, age: 3), ...)
+// age is in seconds.
+// Read transactions are not tracked by the database so are not included.
+func (dbms DbmsLocal) Transactions() *SuObject {
+	list := &SuObject{}
+	for _, ti := range dbms.db.Transactions() {
+		ob := &SuObject{}
+		ob.Set(SuStr("tran"), IntVal(ti.Num))
+		ob.Set(SuStr("type"), SuStr("update"))
+		ob.Set(SuStr("start"), FromTime(ti.Start))
+		ob.Set(SuStr("age"), IntVal(int(time.Since(ti.Start).Seconds())))
+		list.Add(ob)
+	}
+	return list
 }
 
 func (dbms DbmsLocal) Unuse(lib string) bool {
@@ -0,0 +1,64 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package dbms
+
+import (
+	"os"
+	"testing"
+
+	"github.com/apmckinlay/gsuneido/compile"
+	"github.com/apmckinlay/gsuneido/db19"
+	. "github.com/apmckinlay/gsuneido/runtime"
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestSchemaOb(t *testing.T) {
+	assert := assert.T(t).This
+	rq := compile.ParseRequest("create tbl (a,b,c,C) key(a) key(b,c) " +
+		"index(c) index unique(b) in other(x)")
+	ob := schemaOb(&rq.Schema)
+	get := func(ob Value, mem string) Value {
+		return ob.(*SuObject).Get(nil, SuStr(mem))
+	}
+	assert(get(ob, "table")).Is(SuStr("tbl"))
+	assert(get(ob, "columns").String()).Is(`#("a", "b", "c")`)
+	assert(get(ob, "derived").String()).Is(`#("C")`)
+	assert(get(ob, "keys").String()).Is(`#(#("a"), #("b", "c"))`)
+	indexes := get(ob, "indexes").(*SuObject)
+	assert(indexes.ListSize()).Is(4)
+	test := func(i int, cols, mode string) {
+		t.Helper()
+		ix := indexes.ListGet(i)
+		assert(get(ix, "columns").String()).Is(cols)
+		assert(get(ix, "mode")).Is(SuStr(mode))
+	}
+	test(0, `#("a")`, "key")
+	test(1, `#("b", "c")`, "key")
+	test(2, `#("c")`, "index")
+	test(3, `#("b")`, "unique")
+	assert(get(indexes.ListGet(2), "fktable")).Is(nil)
+	assert(get(indexes.ListGet(3), "fktable")).Is(SuStr("other"))
+	assert(get(indexes.ListGet(3), "fkcolumns").String()).Is(`#("x")`)
+}
+
+func TestLocalInfo(t *testing.T) {
+	assert := assert.T(t).This
+	defer os.Remove("tmp.db")
+	db, err := db19.CreateDatabase("tmp.db")
+	assert(err).Is(nil)
+	defer db.Close()
+	dbms := NewDbmsLocal(db)
+
+	assert(dbms.Connections().String()).Is(`#("local")`)
+	info := dbms.Info().(*SuObject)
+	for _, mem := range []string{"uptime", "connections", "transactions",
+		"currentSize", "chunks", "chunkSize", "mapped"} {
+		assert(info.HasKey(SuStr(mem))).Msg(mem).Is(true)
+	}
+	assert(info.Get(nil, SuStr("connections"))).Is(One)
+	assert(info.Get(nil, SuStr("transactions"))).Is(Zero)
+	assert(info.Get(nil, SuStr("currentSize"))).Is(Int64Val(dbms.Size()))
+	assert(dbms.Size() > 0).Is(true)
+	assert(dbms.Transactions().ListSize()).Is(0)
+}
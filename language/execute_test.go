@@ -37,6 +37,21 @@ func TestNaming(t *testing.T) {
 		"Tmp.A ?.B")
 }
 
+func TestForInIx(t *testing.T) {
+	test := func(src, expected string) {
+		t.Helper()
+		c := compile.Constant("function () {\n" + src + "\n}").(*SuFunc)
+		result := NewThread().Start(c, nil)
+		assert.T(t).This(result).Is(SuStr(expected))
+	}
+	test(`s = ''; for i, x in #(a, b, c) { s $= i $ x }; s`, "0a1b2c")
+	test(`s = ''; for (i, x in Seq(5, 8)) s $= i $ x; s`, "051627")
+	test(`s = ''; for i, x in #() { s $= i $ x }; s`, "")
+	test(`s = ''; for i, x in 'abc' { if i is 1 continue; s $= x }; s`, "ac")
+	test(`s = ''; for i, x in #(a, b, c) { if i is 1 break; s $= x }; s`, "a")
+	test(`s = ''; for i, x in #(a, b) { b = { s $= i $ x }; b() }; s`, "0a1b")
+}
+
 func BenchmarkCat(b *testing.B) {
 	c := compile.Constant(
 		`function ()
@@ -39,6 +39,19 @@ func (lxr *Lexer) Position() int {
 	return lxr.si
 }
 
+// SetPosition makes the lexer continue from the given source position.
+// The lexer has no state other than the position
+// so the result is the same as a full lex provided pos is at a token boundary
+// (e.g. the Pos of a previous Item) and not inside a multi-line token
+// such as a string or comment. It is up to the caller to pick a safe position.
+func (lxr *Lexer) SetPosition(pos int) {
+	if pos < 0 || pos > len(lxr.src) {
+		panic("lexer: position out of range")
+	}
+	lxr.si = pos
+	lxr.ahead = nil
+}
+
 // Item is the return value from Lexer.Next
 type Item struct {
 	Text  string
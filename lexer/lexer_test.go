@@ -119,6 +119,17 @@ func TestAhead(t *testing.T) {
 	assert(lxr.Next().Token).Is(tok.Eof)
 }
 
+func TestSetPosition(t *testing.T) {
+	assert := assert.T(t).This
+	lxr := NewLexer("a = /* x */ 1")
+	assert(lxr.Ahead(2)).Is(it(tok.Eq, 2, "="))
+	lxr.SetPosition(4)
+	assert(lxr.Next()).Is(it(tok.Comment, 4, "/* x */"))
+	lxr.SetPosition(7) // not a safe position
+	assert(lxr.Next()).Is(it(tok.Identifier, 7, "x"))
+	assert(func() { lxr.SetPosition(99) }).Panics("out of range")
+}
+
 func TestAheadSkip(t *testing.T) {
 	assert := assert.T(t).This
 	lxr := NewLexer(" a \n= /**/ 1 ")
@@ -24,6 +24,19 @@ var (
 	Errlog = "error.log"
 )
 
+// VerifyChecksums makes database record reads verify the record checksum.
+// This catches corruption early at the cost of per-read overhead
+// so it is off by default.
+var VerifyChecksums = false
+
+// StateWriter makes database states include the host name, process id,
+// and version of the writer, for diagnostics.
+var StateWriter = false
+
+// MaxRecordSize is the largest record (in bytes) that may be output.
+// It must be less than the storage chunk size.
+var MaxRecordSize = 16 * 1024 * 1024 // 16 mb
+
 // debugging options
 const (
 	ThreadDisabled        = false
@@ -89,6 +89,11 @@ func disasm1(fn *SuFunc, i int, indent int) (int, string) {
 		j := fetchInt16()
 		idx := fetchUint8()
 		s += " " + fn.Names[idx] + fmt.Sprint(" ", i+j-1)
+	case op.ForInIx:
+		j := fetchInt16()
+		ix := fetchUint8()
+		idx := fetchUint8()
+		s += " " + fn.Names[ix] + " " + fn.Names[idx] + fmt.Sprint(" ", i+j-2)
 	case op.Try:
 		j := fetchInt16()
 		v := fn.Values[fetchUint8()]
@@ -106,6 +111,22 @@ func disasm1(fn *SuFunc, i int, indent int) (int, string) {
 	return i, s
 }
 
+// DisasmOps calls fn with the offset, opcode, and operands
+// of each top level instruction. Nested functions are not included.
+func DisasmOps(fn *SuFunc, f func(ip int, op, arg string)) {
+	var s string
+	for i := 0; i < len(fn.Code); {
+		j := i
+		i, s = disasm1(fn, i, 0)
+		s = str.BeforeFirst(s, "\n")
+		op, arg := s, ""
+		if k := strings.IndexByte(s, ' '); k != -1 {
+			op, arg = s[:k], s[k+1:]
+		}
+		f(j, op, arg)
+	}
+}
+
 func DisasmMixed(w io.Writer, fn *SuFunc, src string) {
 	sp := fn.SrcBase
 	printSrc := func(s string) {
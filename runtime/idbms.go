@@ -46,6 +46,7 @@ type IDbms interface {
 	Info() Value
 
 	// Kill terminates connections with the given session id.
+	// Standalone (DbmsLocal) it aborts the given transaction number.
 	// It returns the count of connections ended.
 	Kill(string) int
 
@@ -95,6 +96,14 @@ type IDbms interface {
 	Use(lib string) bool
 }
 
+// ISchema is implemented by an IDbms that can describe table schemas.
+// Currently that is only the local dbms, not the client.
+type ISchema interface {
+	// Schema returns an object describing a table's
+	// columns, keys, and indexes, or False if the table doesn't exist
+	Schema(table string) Value
+}
+
 // ITran is the interface to a database transaction,
 // either local (not implemented yet) or TranClient.
 type ITran interface {
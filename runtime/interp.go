@@ -474,6 +474,23 @@ loop:
 			} else {
 				fr.ip += brk - 1 // break
 			}
+		case op.ForInIx:
+			brk := fetchInt16()
+			ix := fetchUint8()
+			local := fetchUint8()
+			i := t.Pop()
+			nextable := t.Top().(interface{ Next() Value })
+			next := nextable.Next()
+			if next != nil {
+				t.Push(OpAdd(i, One))
+				fr.locals.Lock()
+				fr.locals.v[ix] = i
+				fr.locals.v[local] = next
+				fr.locals.Unlock()
+			} else {
+				t.Push(i)
+				fr.ip += brk - 2 // break
+			}
 		case op.ReturnNil:
 			t.Push(nil)
 			fallthrough
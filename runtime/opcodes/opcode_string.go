@@ -68,28 +68,29 @@ func _() {
 	_ = x[JumpIsnt-57]
 	_ = x[Iter-58]
 	_ = x[ForIn-59]
-	_ = x[Throw-60]
-	_ = x[Try-61]
-	_ = x[Catch-62]
-	_ = x[CallFuncDiscard-63]
-	_ = x[CallFuncNoNil-64]
-	_ = x[CallFuncNilOk-65]
-	_ = x[CallMethDiscard-66]
-	_ = x[CallMethNoNil-67]
-	_ = x[CallMethNilOk-68]
-	_ = x[Super-69]
-	_ = x[Return-70]
-	_ = x[ReturnNil-71]
-	_ = x[Closure-72]
-	_ = x[BlockBreak-73]
-	_ = x[BlockContinue-74]
-	_ = x[BlockReturn-75]
-	_ = x[BlockReturnNil-76]
+	_ = x[ForInIx-60]
+	_ = x[Throw-61]
+	_ = x[Try-62]
+	_ = x[Catch-63]
+	_ = x[CallFuncDiscard-64]
+	_ = x[CallFuncNoNil-65]
+	_ = x[CallFuncNilOk-66]
+	_ = x[CallMethDiscard-67]
+	_ = x[CallMethNoNil-68]
+	_ = x[CallMethNilOk-69]
+	_ = x[Super-70]
+	_ = x[Return-71]
+	_ = x[ReturnNil-72]
+	_ = x[Closure-73]
+	_ = x[BlockBreak-74]
+	_ = x[BlockContinue-75]
+	_ = x[BlockReturn-76]
+	_ = x[BlockReturnNil-77]
 }
 
-const _Opcode_name = "NopPopDupSwapIntValueTrueFalseZeroOneMaxIntEmptyStrLoadStoreLoadLockStoreUnlockDyloadGlobalGetPutGetLockPutUnlockRangeToRangeLenThisIsIsntMatchMatchNotLtLteGtGteAddSubCatMulDivModLeftShiftRightShiftBitOrBitAndBitXorBitNotNotUnaryPlusUnaryMinusOrAndBoolQMarkInJumpJumpTrueJumpFalseJumpIsJumpIsntIterForInThrowTryCatchCallFuncDiscardCallFuncNoNilCallFuncNilOkCallMethDiscardCallMethNoNilCallMethNilOkSuperReturnReturnNilClosureBlockBreakBlockContinueBlockReturnBlockReturnNil"
+const _Opcode_name = "NopPopDupSwapIntValueTrueFalseZeroOneMaxIntEmptyStrLoadStoreLoadLockStoreUnlockDyloadGlobalGetPutGetLockPutUnlockRangeToRangeLenThisIsIsntMatchMatchNotLtLteGtGteAddSubCatMulDivModLeftShiftRightShiftBitOrBitAndBitXorBitNotNotUnaryPlusUnaryMinusOrAndBoolQMarkInJumpJumpTrueJumpFalseJumpIsJumpIsntIterForInForInIxThrowTryCatchCallFuncDiscardCallFuncNoNilCallFuncNilOkCallMethDiscardCallMethNoNilCallMethNilOkSuperReturnReturnNilClosureBlockBreakBlockContinueBlockReturnBlockReturnNil"
 
-var _Opcode_index = [...]uint16{0, 3, 6, 9, 13, 16, 21, 25, 30, 34, 37, 43, 51, 55, 60, 68, 79, 85, 91, 94, 97, 104, 113, 120, 128, 132, 134, 138, 143, 151, 153, 156, 158, 161, 164, 167, 170, 173, 176, 179, 188, 198, 203, 209, 215, 221, 224, 233, 243, 245, 248, 252, 257, 259, 263, 271, 280, 286, 294, 298, 303, 308, 311, 316, 331, 344, 357, 372, 385, 398, 403, 409, 418, 425, 435, 448, 459, 473}
+var _Opcode_index = [...]uint16{0, 3, 6, 9, 13, 16, 21, 25, 30, 34, 37, 43, 51, 55, 60, 68, 79, 85, 91, 94, 97, 104, 113, 120, 128, 132, 134, 138, 143, 151, 153, 156, 158, 161, 164, 167, 170, 173, 176, 179, 188, 198, 203, 209, 215, 221, 224, 233, 243, 245, 248, 252, 257, 259, 263, 271, 280, 286, 294, 298, 303, 310, 315, 318, 323, 338, 351, 364, 379, 392, 405, 410, 416, 425, 432, 442, 455, 466, 480}
 
 func (i Opcode) String() string {
 	if i >= Opcode(len(_Opcode_index)-1) {
@@ -165,6 +165,10 @@ const (
 	// if the result is equal to top, it jumps
 	// else it continues
 	ForIn
+	// ForInIx <int16> <uint8> <uint8> is like ForIn
+	// but also sets the first local to the index,
+	// which is kept on the stack above the iterator
+	ForInIx
 
 	// exceptions ---------------------------------------------------
 
@@ -51,7 +51,7 @@ func OpAdd(x Value, y Value) Value {
 			return IntVal(xi + yi)
 		}
 	}
-	return SuDnum{Dnum: dnum.Add(ToDnum(x), ToDnum(y))}
+	return currencyResult(x, y, dnum.Add(ToDnum(x), ToDnum(y)))
 }
 
 func OpSub(x Value, y Value) Value {
@@ -60,7 +60,7 @@ func OpSub(x Value, y Value) Value {
 			return IntVal(xi - yi)
 		}
 	}
-	return SuDnum{Dnum: dnum.Sub(ToDnum(x), ToDnum(y))}
+	return currencyResult(x, y, dnum.Sub(ToDnum(x), ToDnum(y)))
 }
 
 func OpMul(x Value, y Value) Value {
@@ -69,7 +69,7 @@ func OpMul(x Value, y Value) Value {
 			return IntVal(xi * yi)
 		}
 	}
-	return SuDnum{Dnum: dnum.Mul(ToDnum(x), ToDnum(y))}
+	return currencyResult(x, y, dnum.Mul(ToDnum(x), ToDnum(y)))
 }
 
 func OpDiv(x Value, y Value) Value {
@@ -80,11 +80,51 @@ func OpDiv(x Value, y Value) Value {
 			}
 		}
 	}
-	return SuDnum{Dnum: dnum.Div(ToDnum(x), ToDnum(y))}
+	return currencyResult(x, y, dnum.Div(ToDnum(x), ToDnum(y)))
 }
 
+// OpMod truncates non-integer operands (like cSuneido)
+// The result has the sign of x (e.g. -7 % 3 is -1)
+// The result is always a plain number, even for SuCurrency operands.
 func OpMod(x Value, y Value) Value {
-	return IntVal(ToInt(x) % ToInt(y))
+	if xi, xok := SuIntToInt(x); xok {
+		if yi, yok := SuIntToInt(y); yok && yi != 0 {
+			return IntVal(xi % yi)
+		}
+	}
+	yi := truncInt(y)
+	if yi == 0 {
+		panic("modulo by zero")
+	}
+	return Int64Val(truncInt(x) % yi)
+}
+
+func truncInt(x Value) int64 {
+	if i, ok := ToDnum(x).Trunc().ToInt64(); ok {
+		return i
+	}
+	panic("can't convert " + ErrType(x) + " to integer")
+}
+
+// OpPow returns x raised to the power y.
+// Small non-negative integer powers are done by repeated multiplication
+// to keep the result exact, otherwise it uses float64.
+func OpPow(x Value, y Value) Value {
+	if p, ok := y.ToInt(); ok && 0 <= p && p <= 10 {
+		if p == 0 {
+			return One
+		}
+		result := x
+		for ; p > 1; p-- {
+			result = OpMul(result, x)
+		}
+		return result
+	}
+	f := math.Pow(ToDnum(x).ToFloat(), ToDnum(y).ToFloat())
+	if n := int64(f); f == float64(n) {
+		return Int64Val(n)
+	}
+	return SuDnum{Dnum: dnum.FromFloat(f)}
 }
 
 func OpLeftShift(x Value, y Value) Value {
@@ -134,7 +174,8 @@ func OpBool(x Value) bool {
 }
 
 func OpUnaryPlus(x Value) Value {
-	if _, ok := x.(*smi); ok {
+	switch x.(type) {
+	case *smi, SuCurrency:
 		return x
 	}
 	return SuDnum{Dnum: ToDnum(x)}
@@ -144,6 +185,9 @@ func OpUnaryMinus(x Value) Value {
 	if xi, ok := SuIntToInt(x); ok {
 		return IntVal(-xi)
 	}
+	if c, ok := x.(SuCurrency); ok {
+		return SuCurrency{SuDnum: SuDnum{Dnum: c.Neg()}, Scale: c.Scale}
+	}
 	return SuDnum{Dnum: ToDnum(x).Neg()}
 }
 
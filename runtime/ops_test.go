@@ -4,6 +4,7 @@
 package runtime
 
 import (
+	"strconv"
 	"strings"
 	"testing"
 
@@ -20,6 +21,41 @@ func TestDiv(t *testing.T) {
 	_ = q.(SuDnum)
 }
 
+func TestMod(t *testing.T) {
+	num := func(s string) Value {
+		return SuDnum{Dnum: dnum.FromStr(s)}
+	}
+	test := func(x, y, expected Value) {
+		t.Helper()
+		assert.T(t).This(OpMod(x, y)).Is(expected)
+	}
+	test(SuInt(8), SuInt(3), SuInt(2))
+	test(SuInt(-7), SuInt(3), SuInt(-1))
+	test(SuInt(7), SuInt(-3), SuInt(1))
+	test(SuInt(-7), SuInt(-3), SuInt(-1))
+	test(SuInt(0), SuInt(5), SuInt(0))
+	test(num("8.9"), SuInt(3), SuInt(2))
+	test(num("-8.9"), SuInt(3), SuInt(-2))
+	test(SuInt(8), num("3.5"), SuInt(2))
+	test(num("1e10"), SuInt(7), Int64Val(10000000000%7))
+	assert.T(t).This(func() { OpMod(SuInt(1), SuInt(0)) }).Panics("modulo by zero")
+	assert.T(t).This(func() { OpMod(num("1.5"), num(".5")) }).
+		Panics("modulo by zero")
+}
+
+func TestPow(t *testing.T) {
+	num := func(s string) Value {
+		return SuDnum{Dnum: dnum.FromStr(s)}
+	}
+	assert := assert.T(t).This
+	assert(OpPow(SuInt(2), SuInt(0))).Is(SuInt(1))
+	assert(OpPow(SuInt(2), SuInt(10))).Is(SuInt(1024))
+	assert(OpPow(num("1.1"), SuInt(2))).Is(num("1.21"))
+	assert(OpPow(SuInt(2), SuInt(20))).Is(IntVal(1 << 20))
+	assert(OpPow(SuInt(4), num(".5"))).Is(SuInt(2))
+	assert(OpPow(SuInt(2), SuInt(-1))).Is(num(".5"))
+}
+
 func TestBool(t *testing.T) {
 	assert.T(t).That(SuBool(true) == True)
 	assert.T(t).That(SuBool(false) == False)
@@ -42,6 +78,22 @@ func BenchmarkCat(b *testing.B) {
 	}
 }
 
+// BenchmarkCatScaling shows that repeated OpCat is linear, not quadratic
+// i.e. ns/op should grow roughly 10x for each 10x in n
+func BenchmarkCatScaling(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s := EmptyStr
+				for j := 0; j < n; j++ {
+					s = OpCat(nil, s, abc)
+				}
+				G = s
+			}
+		})
+	}
+}
+
 func BenchmarkJoin(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		ob := NewSuObject()
@@ -21,6 +21,34 @@ func TestPack(t *testing.T) {
 	}
 }
 
+func TestPackValue(t *testing.T) {
+	assert := assert.T(t).This
+	ob := &SuObject{}
+	ob.Add(SuInt(1))
+	ob.Add(SuStr("two"))
+	ob.Set(SuStr("date"), NewDate(2020, 2, 29, 12, 34, 56, 789))
+	// in comparison order
+	values := []Value{False, True, dv("-inf"), dv("-1e9"), SuInt(-123),
+		dv("-.5"), SuInt(0), dv(".001"), SuInt(1), dv("123.456"), SuInt(1000),
+		dv("1e9"), dv("inf"), SuStr("\x00"), SuStr("abc"), SuStr("abd"),
+		NewDate(1900, 1, 1, 0, 0, 0, 0),
+		NewDate(2020, 2, 29, 12, 34, 56, 789),
+		NewDate(2020, 2, 29, 12, 34, 56, 790), ob}
+	for i, v := range values {
+		p := PackValue(v)
+		assert(Unpack(p)).Is(v)
+		if i > 0 {
+			prev := values[i-1]
+			assert(prev.Compare(v)).Is(-1)
+			assert(PackValue(prev) < p).Msg(prev, " < ", v).Is(true)
+		}
+	}
+	// empty string is a special case, it packs to zero length
+	assert(PackValue(EmptyStr)).Is("")
+	assert(Unpack("")).Is(EmptyStr)
+	assert(func() { PackValue(&SuClass{}) }).Panics("can't pack")
+}
+
 func TestPackSuInt(t *testing.T) {
 	test := func(n int, expected ...byte) {
 		t.Helper()
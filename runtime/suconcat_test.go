@@ -47,3 +47,19 @@ func TestSuConcat_Equals(t *testing.T) {
 		}
 	}
 }
+
+// SuConcat keeps its contents in a single contiguous buffer
+// so there is no tree to flatten, reads see the same bytes as a SuStr
+func TestSuConcat_Flat(t *testing.T) {
+	var s Value = EmptyStr
+	expected := ""
+	for i := 0; i < 1000; i++ {
+		s = OpCat(nil, s, SuStr("abc"))
+		expected += "abc"
+	}
+	_ = s.(SuConcat)
+	assert.T(t).This(ToStr(s)).Is(expected)
+	assert.T(t).That(s.Equal(SuStr(expected)))
+	assert.T(t).This(s.RangeLen(1500, 3)).Is(SuStr(expected[1500:1503]))
+	assert.T(t).This(s.Get(nil, SuInt(2999))).Is(SuStr("c"))
+}
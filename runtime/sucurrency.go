@@ -0,0 +1,66 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package runtime
+
+import (
+	"strings"
+
+	"github.com/apmckinlay/gsuneido/util/dnum"
+	"github.com/apmckinlay/gsuneido/util/ints"
+)
+
+// SuCurrency is a number with a fixed number of decimal places.
+// Values are rounded to Scale places with banker's rounding (HalfEven).
+// It embeds SuDnum so it packs, compares, and hashes the same as SuDnum.
+// NOTE: The scale is not packed so Unpack returns a plain number.
+//
+// Arithmetic (OpAdd, OpSub, OpMul, OpDiv) with a SuCurrency operand
+// returns a SuCurrency with the larger of the operand scales.
+// Unary plus and minus keep the scale.
+// OpMod truncates to integers so it returns a plain number.
+type SuCurrency struct {
+	SuDnum
+	Scale int
+}
+
+// NewSuCurrency returns a SuCurrency with the value rounded to scale places
+func NewSuCurrency(dn dnum.Dnum, scale int) SuCurrency {
+	return SuCurrency{SuDnum: SuDnum{Dnum: dn.Round(scale, dnum.HalfEven)},
+		Scale: scale}
+}
+
+var _ Value = SuCurrency{}
+
+// String shows Scale decimal places e.g. Currency(1.5) is 1.50
+func (c SuCurrency) String() string {
+	if c.Scale <= 0 || c.IsInf() {
+		return c.SuDnum.String()
+	}
+	before := ints.Max(c.Exp(), 1)
+	return c.Format("-" + strings.Repeat("#", before) + "." +
+		strings.Repeat("#", c.Scale))
+}
+
+func (c SuCurrency) Equal(other interface{}) bool {
+	if x, ok := other.(Value); ok && x.Type() == c.Type() {
+		return dnum.Equal(c.Dnum, ToDnum(x))
+	}
+	return false
+}
+
+// currencyResult returns dn as a SuCurrency
+// if either x or y is a SuCurrency, otherwise as a SuDnum
+func currencyResult(x, y Value, dn dnum.Dnum) Value {
+	scale, isCurrency := 0, false
+	if c, ok := x.(SuCurrency); ok {
+		scale, isCurrency = c.Scale, true
+	}
+	if c, ok := y.(SuCurrency); ok {
+		scale, isCurrency = ints.Max(scale, c.Scale), true
+	}
+	if isCurrency {
+		return NewSuCurrency(dn, scale)
+	}
+	return SuDnum{Dnum: dn}
+}
@@ -0,0 +1,73 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/apmckinlay/gsuneido/util/assert"
+	"github.com/apmckinlay/gsuneido/util/dnum"
+)
+
+func TestSuCurrency(t *testing.T) {
+	assert := assert.T(t).This
+	cur := func(s string) SuCurrency {
+		return NewSuCurrency(dnum.FromStr(s), 2)
+	}
+	num := func(s string) Value {
+		return SuDnum{Dnum: dnum.FromStr(s)}
+	}
+	// rounding at the scale boundary
+	assert(cur("1.005").String()).Is("1.00")
+	assert(cur("1.015").String()).Is("1.02")
+	assert(cur("1.025").String()).Is("1.02")
+	assert(cur("1.0251").String()).Is("1.03")
+	assert(cur("-1.025").String()).Is("-1.02")
+
+	// mixed arithmetic
+	x := OpMul(cur("10.25"), num(".5"))
+	assert(x.(SuCurrency).Scale).Is(2)
+	assert(x.String()).Is("5.12")
+	x = OpAdd(SuInt(1), cur("2.50"))
+	assert(x.(SuCurrency).String()).Is("3.50")
+	x = OpDiv(cur("10"), SuInt(3))
+	assert(x.String()).Is("3.33")
+	x = OpAdd(cur("1.1"), NewSuCurrency(dnum.FromStr("1.125"), 3))
+	assert(x.(SuCurrency).Scale).Is(3)
+	assert(x.String()).Is("2.225")
+	_ = OpAdd(num("1.5"), SuInt(1)).(SuDnum)
+
+	// equality, compare, hash, and pack consistent with numbers
+	assert(cur("3").Equal(SuInt(3))).Is(true)
+	assert(SuInt(3).Equal(cur("3"))).Is(true)
+	assert(num("1.5").Equal(cur("1.5"))).Is(true)
+	assert(cur("1.5").Equal(num("1.5"))).Is(true)
+	assert(cur("1.5").Equal(SuStr("1.5"))).Is(false)
+	assert(cur("3").Hash()).Is(SuInt(3).Hash())
+	assert(cur("1.5").Compare(num("1.25"))).Is(+1)
+	assert(num("1.25").Compare(cur("1.5"))).Is(-1)
+	assert(Pack(cur("1.5"))).Is(Pack(num("1.5").(SuDnum)))
+
+	// String shows the scale
+	assert(cur("1.5").String()).Is("1.50")
+	assert(cur(".5").String()).Is(".50")
+	assert(cur("-1234.5").String()).Is("-1234.50")
+	assert(NewSuCurrency(dnum.FromStr("1.5"), 0).String()).Is("2")
+	assert(NewSuCurrency(dnum.FromStr("1.5"), 3).String()).Is("1.500")
+
+	// unary operations keep the scale, mod does not
+	x = OpUnaryMinus(cur("1.5"))
+	assert(x.(SuCurrency).Scale).Is(2)
+	assert(x.String()).Is("-1.50")
+	assert(OpUnaryPlus(cur("1.5")).String()).Is("1.50")
+	x = OpMod(cur("7.5"), SuInt(2))
+	_ = x.(*smi)
+	assert(x).Is(SuInt(1))
+
+	// the scale is lost by pack/unpack
+	x = Unpack(Pack(cur("1.5")))
+	_, isCurrency := x.(SuCurrency)
+	assert(isCurrency).Is(false)
+	assert(x.String()).Is("1.5")
+}
@@ -72,6 +72,8 @@ func (dn SuDnum) Hash2() uint32 {
 func (dn SuDnum) Equal(other interface{}) bool {
 	if d2, ok := other.(SuDnum); ok {
 		return dnum.Equal(dn.Dnum, d2.Dnum)
+	} else if c, ok := other.(SuCurrency); ok {
+		return dnum.Equal(dn.Dnum, c.Dnum)
 	} else if i, ok := SuIntToInt(other); ok {
 		return dnum.Equal(dn.Dnum, dnum.FromInt(int64(i)))
 	}
@@ -119,6 +119,9 @@ func (si *smi) Equal(other interface{}) bool {
 	} else if dn, ok := other.(SuDnum); ok {
 		dn2, _ := si.ToDnum()
 		return 0 == dnum.Compare(dn2, dn.Dnum)
+	} else if c, ok := other.(SuCurrency); ok {
+		dn2, _ := si.ToDnum()
+		return 0 == dnum.Compare(dn2, c.Dnum)
 	}
 	return false
 }
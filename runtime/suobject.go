@@ -701,7 +701,21 @@ func (ob *SuObject) ArgsIter() func() (Value, Value) {
 
 // Iter2 iterates through list and named elements.
 // List elements are returned with their numeric key index.
+// If the object is concurrent, each step is done with it locked.
 func (ob *SuObject) Iter2(list, named bool) func() (Value, Value) {
+	if !ob.Lock() {
+		return ob.iter2(list, named)
+	}
+	iter := ob.iter2(list, named)
+	ob.Unlock()
+	return func() (Value, Value) {
+		ob.Lock()
+		defer ob.Unlock()
+		return iter()
+	}
+}
+
+func (ob *SuObject) iter2(list, named bool) func() (Value, Value) {
 	version := atomic.LoadInt32(&ob.version)
 	next := 0
 	if list && !named {
@@ -231,3 +231,45 @@ func TestSuObjectCompare(t *testing.T) {
 		y.SetConcurrent()
 	}
 }
+
+func TestSuObjectIterAssocs(t *testing.T) {
+	ob := &SuObject{}
+	ob.Add(SuStr("a"))
+	ob.Add(SuStr("b"))
+	ob.Set(SuStr("x"), One)
+	for i := 0; i < 2; i++ {
+		iter := IterAssocs(ob, true, true)
+		assert.T(t).This(iter.Next().String()).Is(`#(0, "a")`)
+		assert.T(t).This(iter.Next().String()).Is(`#(1, "b")`)
+		assert.T(t).This(iter.Next().String()).Is(`#("x", 1)`)
+		assert.T(t).This(iter.Next()).Is(nil)
+
+		iter = IterAssocs(ob, false, true)
+		assert.T(t).This(iter.Next().String()).Is(`#("x", 1)`)
+		assert.T(t).This(iter.Next()).Is(nil)
+
+		ob.SetConcurrent()
+	}
+}
+
+func TestSuObjectIterConcurrent(t *testing.T) {
+	ob := &SuObject{}
+	for i := 0; i < 100; i++ {
+		ob.Add(IntVal(i))
+	}
+	ob.SetConcurrent()
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			ob.Set(IntVal(i), IntVal(-i))
+		}
+		done <- true
+	}()
+	iter := IterAssocs(ob, true, true)
+	n := 0
+	for x := iter.Next(); x != nil; x = iter.Next() {
+		n++
+	}
+	<-done
+	assert.T(t).This(n).Is(100)
+}
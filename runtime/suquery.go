@@ -17,6 +17,8 @@ type SuQueryCursor struct {
 	query string
 	iqc   IQueryCursor
 	eof   Dir
+	// rc is created by toRecord when it is first needed
+	rc *RowConverter
 }
 
 func newQueryCursor(which string, query string, iqc IQueryCursor) *SuQueryCursor {
@@ -59,6 +61,15 @@ func (qc *SuQueryCursor) String() string {
 	return qc.which + "('" + qc.query + "')"
 }
 
+// toRecord converts a row to an SuRecord
+// reusing a RowConverter for the query's header
+func (qc *SuQueryCursor) toRecord(row Row, tran *SuTran) *SuRecord {
+	if qc.rc == nil {
+		qc.rc = NewRowConverter(qc.iqc.Header())
+	}
+	return qc.rc.ToRecord(row, tran)
+}
+
 //-------------------------------------------------------------------
 
 // ISuQueryCursor is the common interface to SuQuery and SuCursor
@@ -158,7 +169,7 @@ func (q *SuQuery) GetRec(dir Dir) Value {
 		return False
 	}
 	q.eof = 0
-	return SuRecordFromRow(row, q.iqc.Header(), q.tran)
+	return q.toRecord(row, q.tran)
 }
 
 func (q *SuQuery) Output(th *Thread, ob Container) {
@@ -206,5 +217,5 @@ func (q *SuCursor) GetRec(tran *SuTran, dir Dir) Value {
 		return False
 	}
 	q.eof = 0
-	return SuRecordFromRow(row, q.iqc.Header(), tran)
+	return q.toRecord(row, tran)
 }
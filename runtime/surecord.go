@@ -68,29 +68,74 @@ func SuRecordFromObject(ob *SuObject) *SuRecord {
 }
 
 func SuRecordFromRow(row Row, hdr *Header, tran *SuTran) *SuRecord {
+	return NewRowConverter(hdr).ToRecord(row, tran)
+}
+
+// RowConverter converts rows with the same header to SuRecords.
+// It caches the header information (e.g. the _deps fields)
+// so it isn't recomputed for every row.
+type RowConverter struct {
+	hdr *Header
+	// depsFlds are the _deps fields without the suffix
+	depsFlds []string
+	// depsAts are the positions of the _deps fields
+	depsAts []RowAt
+}
+
+func NewRowConverter(hdr *Header) *RowConverter {
 	hdr.EnsureMap()
-	dependents := deps(row, hdr)
-	return &SuRecord{row: row, hdr: hdr, tran: tran, recadr: row[0].Adr,
-		ob: SuObject{defval: EmptyStr}, dependents: dependents, userow: true,
+	rc := &RowConverter{hdr: hdr}
+	for _, f := range hdr.Fields[0] {
+		if strings.HasSuffix(f, "_deps") {
+			rc.depsFlds = append(rc.depsFlds, f[:len(f)-5])
+			rc.depsAts = append(rc.depsAts, hdr.Map[f])
+		}
+	}
+	return rc
+}
+
+// ToRecord returns an SuRecord for a row (with the converter's header)
+func (rc *RowConverter) ToRecord(row Row, tran *SuTran) *SuRecord {
+	return &SuRecord{row: row, hdr: rc.hdr, tran: tran, recadr: row[0].Adr,
+		ob: SuObject{defval: EmptyStr}, dependents: rc.deps(row), userow: true,
 		status: OLD}
 }
 
-func deps(row Row, hdr *Header) map[string][]string {
+func (rc *RowConverter) deps(row Row) map[string][]string {
 	dependents := map[string][]string{}
-	for _, f := range hdr.Fields[0] {
-		if strings.HasSuffix(f, "_deps") {
-			deps := strings.Split(ToStr(row.Get(hdr, f)), ",")
-			f = f[:len(f)-5]
-			for _, d := range deps {
-				if !str.List(dependents[d]).Has(f) {
-					dependents[d] = append(dependents[d], f)
-				}
+	for i, f := range rc.depsFlds {
+		at := rc.depsAts[i]
+		if int(at.Reci) >= len(row) {
+			continue
+		}
+		deps := strings.Split(ToStr(row[at.Reci].GetVal(int(at.Fldi))), ",")
+		for _, d := range deps {
+			if !str.List(dependents[d]).Has(f) {
+				dependents[d] = append(dependents[d], f)
 			}
 		}
 	}
 	return dependents
 }
 
+// RowToObject returns an object with the values of the header columns,
+// including rule columns, but excluding deleted and _deps columns.
+// Missing values are not included.
+func RowToObject(t *Thread, row Row, hdr *Header, tran *SuTran) *SuObject {
+	hdr.EnsureMap()
+	rec := SuRecordFromRow(row, hdr, tran)
+	ob := &SuObject{}
+	for _, col := range hdr.Columns {
+		if col == "-" || strings.HasSuffix(col, "_deps") {
+			continue
+		}
+		if val := rec.GetIfPresent(t, SuStr(col)); val != nil {
+			ob.Set(SuStr(col), val)
+		}
+	}
+	return ob
+}
+
 func (r *SuRecord) Copy() Container {
 	return r.slice(0)
 }
@@ -179,8 +224,20 @@ func (r *SuRecord) ToContainer() (Container, bool) {
 	return r, true
 }
 
+// SetConcurrent makes the record and its contents thread safe.
+// This includes values from the row (as they are unpacked),
+// observers, and attached rules.
 func (r *SuRecord) SetConcurrent() {
+	if r.ob.concurrent {
+		return
+	}
 	r.ob.SetConcurrent()
+	for _, ofn := range r.observers.list {
+		ofn.SetConcurrent()
+	}
+	for _, rule := range r.attachedRules {
+		rule.SetConcurrent()
+	}
 }
 func (r *SuRecord) Lock() bool {
 	return r.ob.Lock()
@@ -416,10 +473,17 @@ func (r *SuRecord) PreSet(key, val Value) {
 }
 
 func (r *SuRecord) Observer(ofn Value) {
+	if r.Lock() {
+		defer r.Unlock()
+		ofn.SetConcurrent()
+	}
 	r.observers.Push(ofn)
 }
 
 func (r *SuRecord) RemoveObserver(ofn Value) bool {
+	if r.Lock() {
+		defer r.Unlock()
+	}
 	return r.observers.Remove(ofn)
 }
 
@@ -667,6 +731,10 @@ func (r *SuRecord) getRule(t *Thread, key string) Value {
 }
 
 func (r *SuRecord) AttachRule(key, callable Value) {
+	if r.Lock() {
+		defer r.Unlock()
+		callable.SetConcurrent()
+	}
 	if r.attachedRules == nil {
 		r.attachedRules = make(map[string]Value)
 	}
@@ -4,6 +4,7 @@
 package runtime
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/apmckinlay/gsuneido/runtime/types"
@@ -36,3 +37,106 @@ func TestSuRecord_ReadonlyUnpack(t *testing.T) {
 	surec.SetReadOnly()
 	assert.T(t).This(surec.Get(nil, SuStr("num"))).Is(SuInt(123))
 }
+
+func TestRowConverter(t *testing.T) {
+	assert := assert.T(t).This
+	hdr := &Header{Columns: []string{"a", "b", "c", "c_deps"},
+		Fields: [][]string{{"a", "b", "c_deps"}, {"c"}}}
+	rc := NewRowConverter(hdr)
+	for i := 0; i < 3; i++ {
+		b := RecordBuilder{}
+		b.Add(SuInt(i))
+		b.Add(SuStr("foo"))
+		b.Add(SuStr("a,b"))
+		b2 := RecordBuilder{}
+		b2.Add(SuInt(i * 10))
+		row := Row{DbRec{Record: b.Build()}, DbRec{Record: b2.Build()}}
+		surec := rc.ToRecord(row, nil)
+		assert(surec.Get(nil, SuStr("a"))).Is(SuInt(i))
+		assert(surec.Get(nil, SuStr("b"))).Is(SuStr("foo"))
+		assert(surec.Get(nil, SuStr("c"))).Is(SuInt(i * 10))
+		assert(surec.dependents).
+			Is(map[string][]string{"a": {"c"}, "b": {"c"}})
+	}
+}
+
+func TestRowToObject(t *testing.T) {
+	hdr := &Header{Columns: []string{"a", "b", "b_lower!", "c", "c_deps", "r"},
+		Fields: [][]string{{"a", "-", "b", "c_deps"}, {"c"}}}
+	b := RecordBuilder{}
+	b.Add(SuInt(1))
+	b.Add(SuStr("deleted"))
+	b.Add(SuStr("Foo"))
+	b.Add(SuStr("a"))
+	b2 := RecordBuilder{}
+	b2.Add(SuInt(10))
+	row := Row{DbRec{Record: b.Build()}, DbRec{Record: b2.Build()}}
+	Global.TestDef("Rule_r", &SuBuiltinMethod0{SuBuiltin1: SuBuiltin1{
+		Fn: func(this Value) Value {
+			return OpAdd(this.Get(nil, SuStr("a")), this.Get(nil, SuStr("c")))
+		}}})
+	ob := RowToObject(NewThread(), row, hdr, nil)
+	assert := assert.T(t).This
+	assert(ob.NamedSize()).Is(5)
+	assert(ob.Get(nil, SuStr("a"))).Is(SuInt(1))
+	assert(ob.Get(nil, SuStr("b"))).Is(SuStr("Foo"))
+	assert(ob.Get(nil, SuStr("b_lower!"))).Is(SuStr("foo"))
+	assert(ob.Get(nil, SuStr("c"))).Is(SuInt(10))
+	assert(ob.Get(nil, SuStr("r"))).Is(SuInt(11))
+}
+
+func TestSuRecord_SetConcurrent(t *testing.T) {
+	nested := NewSuObject(SuInt(1), SuInt(2))
+	b := RecordBuilder{}
+	b.Add(SuStr("foo"))
+	b.Add(nested)
+	hdr := &Header{Columns: []string{"a", "ob"}, Fields: [][]string{{"a", "ob"}}}
+	hdr.EnsureMap()
+	rec := SuRecordFromRow(Row{DbRec{Record: b.Build()}}, hdr, nil)
+	rec.AttachRule(SuStr("r"), NewSuObject())
+	rec.SetConcurrent()
+	assert.T(t).True(rec.IsConcurrent())
+	assert.T(t).True(rec.attachedRules["r"].(*SuObject).IsConcurrent())
+	ob := rec.Get(nil, SuStr("ob")).(*SuObject) // unpacked from row
+	assert.T(t).True(ob.IsConcurrent())
+	rec.Put(nil, SuStr("x"), NewSuObject())
+	assert.T(t).True(rec.Get(nil, SuStr("x")).(*SuObject).IsConcurrent())
+
+	// use from multiple goroutines, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ob := rec.Get(nil, SuStr("ob")).(*SuObject)
+				ob.Add(SuInt(j))
+				_ = ob.String()
+				rec.Put(nil, SuStr("a"), SuInt(j))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.T(t).This(rec.Get(nil, SuStr("ob")).(*SuObject).ListSize()).Is(402)
+}
+
+func BenchmarkRowConverter(b *testing.B) {
+	flds := []string{"a", "b", "c", "d", "e", "f", "g", "h", "e_deps"}
+	hdr := &Header{Columns: flds, Fields: [][]string{flds}}
+	rb := RecordBuilder{}
+	for range flds {
+		rb.Add(SuStr("x"))
+	}
+	row := Row{DbRec{Record: rb.Build()}}
+	b.Run("SuRecordFromRow", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SuRecordFromRow(row, hdr, nil)
+		}
+	})
+	b.Run("RowConverter", func(b *testing.B) {
+		rc := NewRowConverter(hdr)
+		for i := 0; i < b.N; i++ {
+			rc.ToRecord(row, nil)
+		}
+	})
+}
@@ -6,6 +6,7 @@ package runtime
 import (
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/apmckinlay/gsuneido/util/assert"
@@ -46,6 +47,14 @@ func TestCompare(t *testing.T) {
 		assert(vals[i-1].Compare(vals[i])).Msg(vals[i-1], vals[i]).Is(-1)
 		assert(vals[i].Compare(vals[i-1])).Is(+1)
 	}
+	// equal values must hash the same
+	for _, s := range []string{"", "foo", strings.Repeat("helloworld", 10)} {
+		c := NewSuConcat().Add(s)
+		assert(c.Equal(SuStr(s))).Is(true)
+		assert(SuStr(s).Equal(c)).Is(true)
+		assert(c.Hash()).Is(SuStr(s).Hash())
+		assert(c.Hash2()).Is(SuStr(s).Hash2())
+	}
 }
 
 func TestIfStr(t *testing.T) {
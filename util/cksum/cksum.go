@@ -38,3 +38,10 @@ func MustCheck(data []byte) {
 		panic("checksum error")
 	}
 }
+
+// Extend returns the full 32 bit checksum cs extended with data.
+// It is for checksums of larger amounts of data written in pieces,
+// start with a cs of zero.
+func Extend(cs uint32, data []byte) uint32 {
+	return crc32.Update(cs, crc32table, data)
+}
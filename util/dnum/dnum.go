@@ -494,6 +494,8 @@ const (
 	Up RoundingMode = iota
 	Down
 	HalfUp
+	// HalfEven rounds halves to the nearest even digit (banker's rounding)
+	HalfEven
 )
 
 // Trunc returns the integer portion (truncating any fractional part)
@@ -508,7 +510,8 @@ func (dn Dnum) integer(mode RoundingMode) Dnum {
 	}
 	if dn.exp <= 0 {
 		if mode == Up ||
-			(mode == HalfUp && dn.exp == 0 && dn.coef >= One.coef*5) {
+			(mode == HalfUp && dn.exp == 0 && dn.coef >= One.coef*5) ||
+			(mode == HalfEven && dn.exp == 0 && dn.coef > One.coef*5) {
 			return New(dn.sign, One.coef, int(dn.exp)+1)
 		}
 		return Zero
@@ -519,7 +522,9 @@ func (dn Dnum) integer(mode RoundingMode) Dnum {
 		return dn
 	}
 	i := dn.coef - frac
-	if (mode == Up && frac > 0) || (mode == HalfUp && frac >= halfpow10[e]) {
+	if (mode == Up && frac > 0) || (mode == HalfUp && frac >= halfpow10[e]) ||
+		(mode == HalfEven && (frac > halfpow10[e] ||
+			(frac == halfpow10[e] && (i/pow10[e])%2 == 1))) {
 		return New(dn.sign, i+pow10[e], int(dn.exp)) // normalize
 	}
 	return Dnum{i, dn.sign, dn.exp}
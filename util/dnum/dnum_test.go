@@ -201,6 +201,26 @@ func Test_Neg(t *testing.T) {
 	Neg("inf", "-inf")
 }
 
+func Test_RoundHalfEven(t *testing.T) {
+	round := func(x string, r int, expected string) {
+		t.Helper()
+		assert.T(t).This(FromStr(x).Round(r, HalfEven).String()).Is(expected)
+	}
+	round("0", 2, "0")
+	round("1.5", 0, "2")
+	round("2.5", 0, "2")
+	round("-2.5", 0, "-2")
+	round("-3.5", 0, "-4")
+	round(".5", 0, "0")
+	round(".51", 0, "1")
+	round("1.005", 2, "1")
+	round("1.015", 2, "1.02")
+	round("1.025", 2, "1.02")
+	round("1.0251", 2, "1.03")
+	round("1.024", 2, "1.02")
+	round("125", -1, "120")
+}
+
 func Test_Compare(t *testing.T) {
 	assert := assert.T(t).This
 	data := []string{
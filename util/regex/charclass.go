@@ -3,7 +3,12 @@
 
 package regex
 
-import "github.com/apmckinlay/gsuneido/util/ascii"
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/apmckinlay/gsuneido/util/ascii"
+)
 
 // Character classes are compiled to either listSet or bitSet instructions
 // listSet is used for small numbers of characters
@@ -150,3 +155,55 @@ func (b *builder) build() inst {
 func matchSet(set string, c byte) bool {
 	return set[c>>3]&(1<<(c&7)) != 0
 }
+
+// utf8 character classes ------------------------------------------------------
+
+// runeSet instruction data is a 32 byte bit set for ASCII characters,
+// followed by two bytes of flags,
+// followed by non-ASCII ranges as pairs of UTF-8 encoded runes
+
+// flags for non-ASCII characters in runeSet
+const (
+	uNegate = 1 << iota
+	uWord
+	uNotWord
+	uDigit
+	uNotDigit
+	uSpace
+	uNotSpace
+	uAlpha
+	uLower
+	uUpper
+)
+
+// runeClass converts a byte oriented character class instruction
+// to a runeSet instruction
+func runeClass(in inst, flags uint16, ranges string) inst {
+	b := builder{isSet: in.op == bitSet, data: []byte(in.data)}
+	b.toSet()
+	data := string(b.data) + string([]byte{byte(flags), byte(flags >> 8)}) +
+		ranges
+	return inst{op: runeSet, data: data}
+}
+
+// matchRunes returns whether a non-ASCII rune is in a runeSet
+func matchRunes(data string, r rune) bool {
+	flags := uint16(data[setSize]) | uint16(data[setSize+1])<<8
+	isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+	m := (flags&uWord != 0 && isWord) ||
+		(flags&uNotWord != 0 && !isWord) ||
+		(flags&uDigit != 0 && unicode.IsDigit(r)) ||
+		(flags&uNotDigit != 0 && !unicode.IsDigit(r)) ||
+		(flags&uSpace != 0 && unicode.IsSpace(r)) ||
+		(flags&uNotSpace != 0 && !unicode.IsSpace(r)) ||
+		(flags&uAlpha != 0 && unicode.IsLetter(r)) ||
+		(flags&uLower != 0 && unicode.IsLower(r)) ||
+		(flags&uUpper != 0 && unicode.IsUpper(r))
+	for ranges := data[setSize+2:]; !m && ranges != ""; {
+		from, n1 := utf8.DecodeRuneInString(ranges)
+		to, n2 := utf8.DecodeRuneInString(ranges[n1:])
+		m = from <= r && r <= to
+		ranges = ranges[n1+n2:]
+	}
+	return m != (flags&uNegate != 0)
+}
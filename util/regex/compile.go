@@ -5,6 +5,7 @@ package regex
 
 import (
 	"strings"
+	"unicode/utf8"
 )
 
 /*
@@ -73,6 +74,15 @@ func Compile(rx string) Pattern {
 	return co.compile()
 }
 
+// CompileUTF8 is like Compile except the pattern treats the string as UTF-8.
+// . and character classes match whole runes,
+// and \w \d \s and the posix classes also match non-ASCII Unicode characters.
+// Compile (byte oriented) is faster and should be preferred for ASCII data.
+func CompileUTF8(rx string) Pattern {
+	co := compiler{src: rx, sn: len(rx), utf8: true}
+	return co.compile()
+}
+
 type compiler struct {
 	src                 string
 	si                  int
@@ -82,15 +92,22 @@ type compiler struct {
 	leftCount           int
 	inChars             bool
 	inCharsIgnoringCase bool
+	utf8                bool
 }
 
 var (
 	left0  = inst{op: left, i: 0}
 	right0 = inst{op: right, i: 0}
+	// left0utf8 marks a pattern compiled by CompileUTF8
+	left0utf8 = inst{op: left, i: 0, data: "utf8"}
 )
 
 func (co *compiler) compile() Pattern {
-	co.emit(left0)
+	if co.utf8 {
+		co.emit(left0utf8)
+	} else {
+		co.emit(left0)
+	}
 	if co.sn >= 2 && co.startsWithAnything() { //BUG has to be inside grouping
 		co.emit(inst{op: startOfLine})
 	}
@@ -196,17 +213,17 @@ func (co *compiler) simple() {
 	if co.match(".") {
 		co.emit(inst{op: dot})
 	} else if co.match("\\d") {
-		co.emit(digit)
+		co.emitClass(digit, uDigit)
 	} else if co.match("\\D") {
-		co.emit(notDigit)
+		co.emitClass(notDigit, uNotDigit)
 	} else if co.match("\\w") {
-		co.emit(word)
+		co.emitClass(word, uWord)
 	} else if co.match("\\W") {
-		co.emit(notWord)
+		co.emitClass(notWord, uNotWord)
 	} else if co.match("\\s") {
-		co.emit(space)
+		co.emitClass(space, uSpace)
 	} else if co.match("\\S") {
-		co.emit(notSpace)
+		co.emitClass(notSpace, uNotSpace)
 	} else if co.matchBackref() {
 		i := int(co.src[co.si-1] - '0')
 		if co.ignoringCase {
@@ -231,8 +248,29 @@ func (co *compiler) simple() {
 		if co.si+1 < co.sn {
 			co.match("\\")
 		}
-		co.si++
-		co.emitChars(co.src[co.si-1 : co.si])
+		start := co.si
+		co.si += co.charLen()
+		co.emitChars(co.src[start:co.si])
+	}
+}
+
+// charLen returns the length of the next character in the source,
+// a whole rune if utf8, otherwise one byte
+func (co *compiler) charLen() int {
+	if co.utf8 {
+		_, n := utf8.DecodeRuneInString(co.src[co.si:])
+		return n
+	}
+	return 1
+}
+
+// emitClass emits a predefined character class.
+// If utf8 it is combined with the Unicode flags.
+func (co *compiler) emitClass(in inst, flags uint16) {
+	if co.utf8 {
+		co.emit(runeClass(in, flags, ""))
+	} else {
+		co.emit(in)
 	}
 }
 
@@ -263,29 +301,52 @@ func (co *compiler) charClass() {
 		chars += "]"
 	}
 	var cc = builder{}
+	var flags uint16 // only used if utf8
+	ranges := ""     // non-ASCII, only used if utf8
 	for co.si < co.sn && co.src[co.si] != ']' {
-		if co.matchRange() {
-			cc.addRange(co.src[co.si-3], co.src[co.si-1])
+		if from, to, ok := co.matchRange(); ok {
+			if !co.utf8 || to < utf8.RuneSelf {
+				cc.addRange(byte(from), byte(to))
+			} else {
+				if from < utf8.RuneSelf {
+					cc.addRange(byte(from), utf8.RuneSelf-1)
+					from = utf8.RuneSelf
+				}
+				ranges += string(from) + string(to)
+			}
 		} else if co.match("\\d") {
 			cc.add(digit)
+			flags |= uDigit
 		} else if co.match("\\D") {
 			cc.add(notDigit)
+			flags |= uNotDigit
 		} else if co.match("\\w") {
 			cc.add(word)
+			flags |= uWord
 		} else if co.match("\\W") {
 			cc.add(notWord)
+			flags |= uNotWord
 		} else if co.match("\\s") {
 			cc.add(space)
+			flags |= uSpace
 		} else if co.match("\\S") {
 			cc.add(notSpace)
+			flags |= uNotSpace
 		} else if co.match("[:") {
-			cc.add(co.posixClass())
+			in, f := co.posixClass()
+			cc.add(in)
+			flags |= f
 		} else {
 			if co.si+1 < co.sn {
 				co.match("\\")
 			}
-			chars += co.src[co.si : co.si+1]
-			co.si++
+			n := co.charLen()
+			if n == 1 {
+				chars += co.src[co.si : co.si+1]
+			} else {
+				ranges += co.src[co.si:co.si+n] + co.src[co.si:co.si+n]
+			}
+			co.si += n
 		}
 	}
 	if len(chars) > 0 {
@@ -293,52 +354,73 @@ func (co *compiler) charClass() {
 	}
 	if negate {
 		cc.negate()
+		flags |= uNegate
 	}
 	// optimization - treat single character class as just character
-	if len(cc.data) == 1 {
+	if len(cc.data) == 1 && (!co.utf8 || flags == 0 && ranges == "") {
 		co.emitChars(string(cc.data[0:1]))
 		return
 	}
 	if co.ignoringCase {
 		cc.ignore()
 	}
-	co.emit(cc.build())
+	if co.utf8 {
+		co.emit(runeClass(cc.build(), flags, ranges))
+	} else {
+		co.emit(cc.build())
+	}
 }
 
-func (co *compiler) matchRange() bool {
-	if co.src[co.si+1] == '-' &&
-		co.si+2 < co.sn && co.src[co.si+2] != ']' {
-		co.si += 3
-		return true
+// matchRange handles char - char.
+// If utf8 the chars may be multi-byte runes, otherwise they are single bytes.
+func (co *compiler) matchRange() (from, to rune, ok bool) {
+	si := co.si
+	n1 := co.charLen()
+	if si+n1 >= co.sn || co.src[si+n1] != '-' {
+		return
 	}
-	return false
+	co.si += n1 + 1
+	if co.si >= co.sn || co.src[co.si] == ']' {
+		co.si = si
+		return
+	}
+	co.si += co.charLen()
+	if co.utf8 {
+		from, _ = utf8.DecodeRuneInString(co.src[si:])
+		to, _ = utf8.DecodeRuneInString(co.src[si+n1+1:])
+	} else {
+		from, to = rune(co.src[si]), rune(co.src[si+n1+1])
+	}
+	return from, to, true
 }
 
-func (co *compiler) posixClass() inst {
+// posixClass returns the character class instruction
+// and the Unicode flags to use if utf8
+func (co *compiler) posixClass() (inst, uint16) {
 	if co.match("alpha:]") {
-		return alpha
+		return alpha, uAlpha
 	} else if co.match("alnum:]") {
-		return alnum
+		return alnum, uWord
 	} else if co.match("blank:]") {
-		return blank
+		return blank, 0
 	} else if co.match("cntrl:]") {
-		return cntrl
+		return cntrl, 0
 	} else if co.match("digit:]") {
-		return digit
+		return digit, uDigit
 	} else if co.match("graph:]") {
-		return graph
+		return graph, 0
 	} else if co.match("lower:]") {
-		return lower
+		return lower, uLower
 	} else if co.match("print:]") {
-		return print
+		return print, 0
 	} else if co.match("punct:]") {
-		return punct
+		return punct, 0
 	} else if co.match("space:]") {
-		return space
+		return space, uSpace
 	} else if co.match("upper:]") {
-		return upper
+		return upper, uUpper
 	} else if co.match("xdigit:]") {
-		return xdigit
+		return xdigit, 0
 	} else {
 		panic("bad posix class")
 	}
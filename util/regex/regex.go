@@ -7,6 +7,7 @@ package regex
 import (
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/apmckinlay/gsuneido/util/ascii"
 	"github.com/apmckinlay/gsuneido/util/assert"
@@ -27,7 +28,8 @@ type inst struct {
 	jump int16
 	// alt is used by branch
 	alt int16
-	// data is used by chars and charclass
+	// data is used by chars and charclass,
+	// and by the initial left to mark a utf8 pattern
 	data string
 }
 
@@ -42,6 +44,8 @@ const (
 	listSet
 	// bitSet is a character class represented as a 32 byte bit set in data
 	bitSet
+	// runeSet is a utf8 character class, see charclass.go
+	runeSet
 	branch
 	jump
 	left
@@ -81,6 +85,7 @@ var ops = map[byte]string{
 	startOfWord:   "\\<",
 	endOfWord:     "\\>",
 	bitSet:        "[...]",
+	runeSet:       "u[...]",
 }
 
 func (in inst) String() string {
@@ -131,6 +136,16 @@ func (pat Pattern) LastMatch(s string, pos int, result *Result) int {
 	return pat.match(s, pos, -1, result)
 }
 
+// AnchoredMatch only tries to match at pos, it does not scan forward.
+// Returns the length of the match, or -1 if it does not match at pos.
+// This is useful for scanners that advance the position themselves.
+func (pat Pattern) AnchoredMatch(s string, pos int, result *Result) int {
+	if pat.match(s, pos, 0, result) == -1 {
+		return -1
+	}
+	return result[0].end - pos
+}
+
 // ForEachMatch calls action for each non-overlapping match in the string.
 // The action should return true to continue, false to stop.
 func (pat Pattern) ForEachMatch(s string, action func(*Result) bool) {
@@ -147,6 +162,12 @@ func (pat Pattern) ForEachMatch(s string, action func(*Result) bool) {
 
 const maxAlt = 100
 
+// MaxBacktrack limits the number of backtracking steps in one match
+// to bound the time pathological patterns can take.
+// If exceeded, match panics with "regex: too complex".
+// The default of zero means unlimited.
+var MaxBacktrack = 0
+
 var repeat = inst{op: branch, jump: -1, alt: 1}
 
 // match searches for a match.
@@ -156,8 +177,10 @@ var repeat = inst{op: branch, jump: -1, alt: 1}
 func (pat Pattern) match(s string, pos, incdec int, result *Result) int {
 	var alts [maxAlt]alternate
 	var tmp [maxResult]int
+	u := pat.isUTF8()
+	nback := 0
 outer:
-	for ; 0 <= pos && pos <= len(s); pos += incdec {
+	for ; 0 <= pos && pos <= len(s); pos = advance(s, pos, incdec, u) {
 		ai := 0
 		si := pos
 		first := 0 // used to identify first non-left pattern element
@@ -169,7 +192,7 @@ outer:
 				if pi+1 < len(pat) && pat[pi+1] == repeat {
 					// for .* or .+ shortcut looping to end of line
 					alts[ai].pi = pi + 2
-					alts[ai].si = si + 1
+					alts[ai].si = step(s, si, u)
 					j := strings.IndexAny(s[si:], "\r\n")
 					if j == -1 {
 						si = len(s)
@@ -181,7 +204,7 @@ outer:
 					pi++
 				} else {
 					m = si < len(s) && s[si] != '\r' && s[si] != '\n'
-					si++
+					si = step(s, si, u)
 				}
 			case chars:
 				if pi == first && incdec == +1 && ai == 0 {
@@ -214,9 +237,23 @@ outer:
 					m = in.data[c>>3]&(1<<(c&7)) != 0
 				}
 				si++
+			case runeSet:
+				m = false
+				n := 1
+				if si < len(s) {
+					if c := s[si]; c < utf8.RuneSelf {
+						m = matchSet(in.data, c)
+					} else {
+						var r rune
+						r, n = utf8.DecodeRuneInString(s[si:])
+						m = matchRunes(in.data, r)
+					}
+				}
+				si += n
 			case branch:
-				if ai > 0 && alts[ai-1].pi == pi+int(in.alt) && si == alts[ai-1].si2+1 {
-					alts[ai-1].si2++ // expand existing entry, avoid stack growth
+				if ai > 0 && alts[ai-1].pi == pi+int(in.alt) &&
+					si == step(s, alts[ai-1].si2, u) {
+					alts[ai-1].si2 = si // expand existing entry, avoid stack growth
 				} else {
 					alts[ai].pi = pi + int(in.alt)
 					alts[ai].si = si
@@ -270,10 +307,13 @@ outer:
 			if !m {
 				if ai > 0 {
 					// backtrack
+					if nback++; MaxBacktrack > 0 && nback > MaxBacktrack {
+						panic("regex: too complex")
+					}
 					pi = alts[ai-1].pi - 1 // -1 because loop increments
 					if alts[ai-1].si2 > alts[ai-1].si {
 						si = alts[ai-1].si2
-						alts[ai-1].si2--
+						alts[ai-1].si2 = stepBack(s, si, u)
 					} else {
 						ai--
 						si = alts[ai].si
@@ -290,6 +330,44 @@ outer:
 	return -1 // didn't match at any position
 }
 
+func (pat Pattern) isUTF8() bool {
+	return len(pat) > 0 && pat[0].data != ""
+}
+
+// advance moves pos forward or backward one character (if incdec is not 0)
+func advance(s string, pos, incdec int, u bool) int {
+	if incdec > 0 {
+		return step(s, pos, u)
+	} else if incdec < 0 {
+		return stepBack(s, pos, u)
+	}
+	return pos
+}
+
+// step returns the position after the character at i.
+// If u it skips a whole rune, otherwise one byte.
+func step(s string, i int, u bool) int {
+	if u && i < len(s) && s[i] >= utf8.RuneSelf {
+		return stepRune(s, i)
+	}
+	return i + 1
+}
+
+func stepRune(s string, i int) int {
+	_, n := utf8.DecodeRuneInString(s[i:])
+	return i + n
+}
+
+// stepBack returns the position of the character before i.
+// If u it backs up to the start of a rune, otherwise one byte.
+func stepBack(s string, i int, u bool) int {
+	i--
+	for u && 0 < i && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
 // hasPrefixIgnore returns whether s has pre as a prefix
 // WARNING: s must be as long as pre
 func hasPrefixIgnore(s, pre string) bool {
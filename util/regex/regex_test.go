@@ -51,6 +51,89 @@ func TestRegex(t *testing.T) {
 	assert(pat.match("hifoobar", 0, 0, &r)).Is(0)
 }
 
+func TestAnchoredMatch(t *testing.T) {
+	test := func(rx, s string, pos int) {
+		t.Helper()
+		var r1, r2 Result
+		n := Compile(rx).AnchoredMatch(s, pos, &r1)
+		// \A only matches at the start of the string so use a slice
+		i := Compile(`\A`+rx).FirstMatch(s[pos:], 0, &r2)
+		if i == -1 {
+			assert.T(t).This(n).Is(-1)
+		} else {
+			assert.T(t).This(n).Is(r2[0].end)
+			assert.T(t).This(r1[0].Part(s)).Is(r2[0].Part(s[pos:]))
+		}
+	}
+	test("foo", "foobar", 0)
+	test("foo", "xfoobar", 0)
+	test("foo", "xfoobar", 1)
+	test("bar", "foobar", 1)
+	test(`\w+`, "now is the time", 4)
+	test(`\w+`, "now is the time", 3)
+	test(`\d+\.\d*`, "x = 12.34;", 4)
+	test("a|bc", "xbcd", 1)
+	test("", "abc", 2)
+	test("c$", "abc", 2)
+	test("abc", "ab", 0)
+}
+
+func TestUTF8(t *testing.T) {
+	test := func(rx, s string, expected string) {
+		t.Helper()
+		var r Result
+		if CompileUTF8(rx).FirstMatch(s, 0, &r) == -1 {
+			assert.T(t).Msg(rx).This("no match").Is(expected)
+		} else {
+			assert.T(t).Msg(rx).This(r[0].Part(s)).Is(expected)
+		}
+	}
+	test(".", "é", "é")
+	test("..", "日本語", "日本")
+	test("a.c", "aéc", "aéc")
+	test(".*", "çà et là", "çà et là")
+	test(".+x", "ééx", "ééx")
+	test("é+", "ééé", "ééé")
+	test("aé?b", "ab", "ab")
+	test(`\w+`, "  naïve café ", "naïve")
+	test(`\W+`, "naïve, café", ", ")
+	test(`\d+`, "x٣٤y", "٣٤")
+	test(`\s`, "a\u00a0b", "\u00a0")
+	test("[éà]+", "xàéy", "àé")
+	test("[^a]", "aéb", "é")
+	test("[α-ω]+", "abγδεz", "γδε")
+	test("[a-ω]+", "XYZabγ", "abγ")
+	test("[[:alpha:]]+", "123Ωmega", "Ωmega")
+	test("[[:upper:]]", "ωΩ", "Ω")
+	test("[^[:lower:]]", "ωΩ", "Ω")
+	test(`\W`, "éx", "no match")
+	test("x", "日本語", "no match")
+
+	// byte oriented default
+	var r Result
+	Compile(".").FirstMatch("é", 0, &r)
+	assert.T(t).This(r[0].end).Is(1)
+	assert.T(t).This(Compile(`\w`).FirstMatch("é", 0, &r)).Is(-1)
+
+	// LastMatch steps back by runes
+	pat := CompileUTF8(`[^a]`)
+	s := "aéé"
+	i := pat.LastMatch(s, len(s), &r)
+	assert.T(t).This(i).Is(3)
+	assert.T(t).This(r[0].Part(s)).Is("é")
+}
+
+func TestMaxBacktrack(t *testing.T) {
+	pat := Compile("(a+)+c")
+	s := strings.Repeat("a", 30)
+	var r Result
+	defer func(prev int) { MaxBacktrack = prev }(MaxBacktrack)
+	MaxBacktrack = 100000
+	assert.T(t).This(func() { pat.FirstMatch(s, 0, &r) }).
+		Panics("regex: too complex")
+	assert.T(t).This(pat.FirstMatch("aaaac", 0, &r)).Is(0)
+}
+
 func TestCapture(t *testing.T) {
 	pat := Compile("is")
 	s := "now is the time"
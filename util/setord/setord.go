@@ -0,0 +1,92 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+// Package setord implements set operations on lists of lists of strings
+// e.g. lists of index columns.
+// Order is preserved, results are in the order of the inputs.
+//
+// WARNING: These operations do not modify their inputs
+// but may return one of them (not a copy) e.g. if the other is empty.
+//
+// WARNING: Duplicates in the inputs may produce duplicates in the outputs.
+package setord
+
+// eq returns whether two lists contain the same strings in the same order
+func eq(x, y []string) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// contains returns whether list contains a list equal to x
+func contains(list [][]string, x []string) bool {
+	for _, y := range list {
+		if eq(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// Difference returns the elements of x that are not in y.
+// If x or y is empty it returns x.
+func Difference(x, y [][]string) [][]string {
+	if len(x) == 0 || len(y) == 0 {
+		return x
+	}
+	z := make([][]string, 0, len(x))
+	for _, xs := range x {
+		if !contains(y, xs) {
+			z = append(z, xs)
+		}
+	}
+	return z
+}
+
+// SymmetricDifference returns the elements that are in exactly one of x or y,
+// those from x followed by those from y.
+// If x is empty it returns y, if y is empty it returns x.
+func SymmetricDifference(x, y [][]string) [][]string {
+	if len(x) == 0 {
+		return y
+	}
+	if len(y) == 0 {
+		return x
+	}
+	z := make([][]string, 0, len(x)+len(y))
+	for _, xs := range x {
+		if !contains(y, xs) {
+			z = append(z, xs)
+		}
+	}
+	for _, ys := range y {
+		if !contains(x, ys) {
+			z = append(z, ys)
+		}
+	}
+	return z
+}
+
+// Dedup returns x with duplicate elements removed,
+// keeping the first occurrence of each.
+// If there are no duplicates it returns x.
+func Dedup(x [][]string) [][]string {
+	for i := 1; i < len(x); i++ {
+		if contains(x[:i], x[i]) {
+			z := append(make([][]string, 0, len(x)-1), x[:i]...)
+			for _, xs := range x[i+1:] {
+				if !contains(z, xs) {
+					z = append(z, xs)
+				}
+			}
+			return z
+		}
+	}
+	return x
+}
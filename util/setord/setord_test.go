@@ -0,0 +1,63 @@
+// Copyright Suneido Software Corp. All rights reserved.
+// Governed by the MIT license found in the LICENSE file.
+
+package setord
+
+import (
+	"testing"
+
+	"github.com/apmckinlay/gsuneido/util/assert"
+)
+
+func TestDifference(t *testing.T) {
+	assert := assert.T(t).This
+	a := []string{"a"}
+	bc := []string{"b", "c"}
+	cb := []string{"c", "b"}
+	d := []string{"d"}
+	var empty [][]string
+	x := [][]string{a, bc}
+	assert(Difference(empty, empty)).Is(empty)
+	assert(Difference(x, empty)).Is(x)
+	assert(Difference(empty, x)).Is(empty)
+	assert(Difference(x, x)).Is([][]string{})
+	assert(Difference(x, [][]string{bc, d})).Is([][]string{a})
+	// order within an element matters
+	assert(Difference(x, [][]string{cb})).Is(x)
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	assert := assert.T(t).This
+	a := []string{"a"}
+	bc := []string{"b", "c"}
+	cb := []string{"c", "b"}
+	d := []string{"d"}
+	var empty [][]string
+	x := [][]string{a, bc}
+	assert(SymmetricDifference(empty, empty)).Is(empty)
+	assert(SymmetricDifference(x, empty)).Is(x)
+	assert(SymmetricDifference(empty, x)).Is(x)
+	assert(SymmetricDifference(x, x)).Is([][]string{})
+	assert(SymmetricDifference(x, [][]string{bc, d})).Is([][]string{a, d})
+	// order within an element matters
+	assert(SymmetricDifference(x, [][]string{cb})).Is([][]string{a, bc, cb})
+	// duplicates are not removed
+	assert(SymmetricDifference([][]string{a, a}, [][]string{d})).
+		Is([][]string{a, a, d})
+}
+
+func TestDedup(t *testing.T) {
+	assert := assert.T(t).This
+	a := []string{"a"}
+	bc := []string{"b", "c"}
+	d := []string{"d"}
+	var empty [][]string
+	assert(Dedup(empty)).Is(empty)
+	x := [][]string{a, bc, d}
+	assert(Dedup(x)).Is(x)
+	// adjacent
+	assert(Dedup([][]string{a, a, bc, bc, bc})).Is([][]string{a, bc})
+	// non-adjacent
+	assert(Dedup([][]string{bc, a, d, a, []string{"b", "c"}})).
+		Is([][]string{bc, a, d})
+}
@@ -20,6 +20,20 @@ func (list List) Has(str string) bool {
 	return false
 }
 
+// Equal returns true if the list has the same strings
+// in the same order as the other list
+func (list List) Equal(other []string) bool {
+	if len(list) != len(other) {
+		return false
+	}
+	for i, s := range list {
+		if s != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // Index returns position of the first occurrence of the given string,
 // or -1 if not found.
 func (list List) Index(str string) int {